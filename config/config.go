@@ -0,0 +1,91 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+
+	"github.com/Alvaroalonsobabbel/wordle/theme"
+)
+
+const configFile = ".wordle_config"
+
+type Config struct {
+	Leaderboard Leaderboard `json:"leaderboard"`
+	// Theme is the name of the theme the game is displayed with, either
+	// a built-in one or one of Themes.
+	Theme     string                 `json:"theme"`
+	Themes    map[string]theme.Theme `json:"themes"`
+	Animation Animation              `json:"animation"`
+	// Storage is the backend games are kept with: "json", the default,
+	// or "sqlite".
+	Storage string `json:"storage"`
+	Share   Share  `json:"share"`
+}
+
+// Share holds how results are shared. Squares are the ones of the absent
+// letters: "light", the default white ones, or "dark" for black ones.
+type Share struct {
+	Squares string `json:"squares"`
+}
+
+// Animation holds how fast animations play and how much the game moves:
+// "full", "reduced" or "off".
+type Animation struct {
+	Speed  float64 `json:"speed"`
+	Motion string  `json:"motion"`
+}
+
+// Leaderboard holds where finished games are posted to. Posting is
+// disabled when URL is empty.
+type Leaderboard struct {
+	URL    string `json:"url"`
+	Player string `json:"player"`
+}
+
+// Load reads the config file from the home directory. A missing
+// file returns the default config.
+func Load() (*Config, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("error getting home directory: %v", err)
+	}
+
+	return load(filepath.Join(homeDir, configFile))
+}
+
+func load(path string) (*Config, error) {
+	c := &Config{}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("error reading config file: %v", err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, c); err != nil {
+			return nil, fmt.Errorf("error decoding config file: %v", err)
+		}
+	}
+
+	switch c.Animation.Motion {
+	case "", "full", "reduced", "off":
+	default:
+		return nil, fmt.Errorf("invalid animation motion %q, choose one of full, reduced or off", c.Animation.Motion)
+	}
+
+	switch c.Share.Squares {
+	case "", "light", "dark":
+	default:
+		return nil, fmt.Errorf("invalid share squares %q, choose one of light or dark", c.Share.Squares)
+	}
+
+	if c.Leaderboard.Player == "" {
+		if u, err := user.Current(); err == nil {
+			c.Leaderboard.Player = u.Username
+		}
+	}
+
+	return c, nil
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Alvaroalonsobabbel/wordle/theme"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	t.Run("missing file returns the default config", func(t *testing.T) {
+		c, err := load(filepath.Join(t.TempDir(), configFile))
+		assert.NoError(t, err)
+		assert.Empty(t, c.Leaderboard.URL)
+		assert.NotEmpty(t, c.Leaderboard.Player)
+	})
+
+	t.Run("reads the leaderboard settings", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), configFile)
+		assert.NoError(t, os.WriteFile(path, []byte(`{"leaderboard":{"url":"http://wordle.lan:8080","player":"alice"}}`), 0600))
+
+		c, err := load(path)
+		assert.NoError(t, err)
+		assert.Equal(t, Leaderboard{URL: "http://wordle.lan:8080", Player: "alice"}, c.Leaderboard)
+	})
+
+	t.Run("reads the themes", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), configFile)
+		assert.NoError(t, os.WriteFile(path, []byte(`{"theme":"mine","themes":{"mine":{"base":"light","correct":{"fg":"#00ff00","bold":true}}}}`), 0600))
+
+		c, err := load(path)
+		assert.NoError(t, err)
+		assert.Equal(t, "mine", c.Theme)
+		assert.Equal(t, map[string]theme.Theme{"mine": {Base: "light", Correct: theme.Style{Foreground: "#00ff00", Bold: true}}}, c.Themes)
+	})
+
+	t.Run("reads the animation settings", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), configFile)
+		assert.NoError(t, os.WriteFile(path, []byte(`{"animation":{"speed":1.5,"motion":"reduced"}}`), 0600))
+
+		c, err := load(path)
+		assert.NoError(t, err)
+		assert.Equal(t, Animation{Speed: 1.5, Motion: "reduced"}, c.Animation)
+	})
+
+	t.Run("reads the storage backend", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), configFile)
+		assert.NoError(t, os.WriteFile(path, []byte(`{"storage":"sqlite"}`), 0600))
+
+		c, err := load(path)
+		assert.NoError(t, err)
+		assert.Equal(t, "sqlite", c.Storage)
+	})
+
+	t.Run("reads the share settings", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), configFile)
+		assert.NoError(t, os.WriteFile(path, []byte(`{"share":{"squares":"dark"}}`), 0600))
+
+		c, err := load(path)
+		assert.NoError(t, err)
+		assert.Equal(t, Share{Squares: "dark"}, c.Share)
+	})
+
+	t.Run("invalid share squares return an error", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), configFile)
+		assert.NoError(t, os.WriteFile(path, []byte(`{"share":{"squares":"grey"}}`), 0600))
+
+		_, err := load(path)
+		assert.EqualError(t, err, `invalid share squares "grey", choose one of light or dark`)
+	})
+
+	t.Run("invalid motion returns an error", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), configFile)
+		assert.NoError(t, os.WriteFile(path, []byte(`{"animation":{"motion":"wild"}}`), 0600))
+
+		_, err := load(path)
+		assert.EqualError(t, err, `invalid animation motion "wild", choose one of full, reduced or off`)
+	})
+
+	t.Run("invalid file returns an error", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), configFile)
+		assert.NoError(t, os.WriteFile(path, []byte(`{`), 0600))
+
+		_, err := load(path)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,184 @@
+package leaderboard
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Alvaroalonsobabbel/wordle/status"
+	"github.com/Alvaroalonsobabbel/wordle/wordle"
+)
+
+const (
+	resultsPath   = "/api/results"
+	clientTimeout = 5 * time.Second
+)
+
+var ErrQueued = errors.New("leaderboard unreachable, the result will be posted later")
+
+// Client posts finished games to a leaderboard server. Submissions go
+// through a queue persisted in a file so games finished while offline
+// are posted the next time the queue is flushed.
+type Client struct {
+	mu     sync.Mutex
+	url    string
+	player string
+	queue  string
+	http   *http.Client
+}
+
+func NewClient(baseURL, player, queuePath string) (*Client, error) {
+	u, err := url.JoinPath(baseURL, resultsPath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid leaderboard url: %v", err)
+	}
+
+	return &Client{
+		url:    u,
+		player: player,
+		queue:  queuePath,
+		http:   &http.Client{Timeout: clientTimeout},
+	}, nil
+}
+
+// Post queues the finished game and flushes the queue. Only the result of
+// posting this game is returned, the other queued games fail quietly.
+func (c *Client) Post(s *wordle.Status) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	queue, err := c.load()
+	if err != nil {
+		return err
+	}
+
+	sub := Submission{
+		Player:       c.player,
+		PuzzleNumber: s.PuzzleNumber,
+		HardMode:     s.HardMode,
+		Share:        s.Share(),
+		Results:      s.Results,
+	}
+	for i, q := range queue {
+		if q.PuzzleNumber == sub.PuzzleNumber {
+			queue = append(queue[:i], queue[i+1:]...)
+			break
+		}
+	}
+	if err := c.save(append(queue, sub)); err != nil {
+		return err
+	}
+
+	errs, err := c.flush()
+	if err != nil {
+		return err
+	}
+
+	return errs[sub.PuzzleNumber]
+}
+
+// Flush posts every queued submission.
+func (c *Client) Flush() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	errs, err := c.flush()
+	if err != nil {
+		return err
+	}
+
+	return errors.Join(slices.Collect(maps.Values(errs))...)
+}
+
+// flush sends the queued submissions and returns the error of each one
+// that failed by puzzle number.
+func (c *Client) flush() (map[int]error, error) {
+	queue, err := c.load()
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Submission
+	errs := make(map[int]error)
+	for _, sub := range queue {
+		if err := c.send(sub); err != nil {
+			if errors.Is(err, ErrQueued) {
+				pending = append(pending, sub)
+			}
+			errs[sub.PuzzleNumber] = err
+		}
+	}
+
+	if err := c.save(pending); err != nil {
+		return nil, err
+	}
+
+	return errs, nil
+}
+
+// send posts a submission. Network and server errors return ErrQueued
+// so the submission is retried later, rejected submissions are dropped.
+func (c *Client) send(sub Submission) error {
+	body, err := json.Marshal(sub)
+	if err != nil {
+		return fmt.Errorf("error encoding submission: %v", err)
+	}
+
+	resp, err := c.http.Post(c.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return ErrQueued
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusCreated, resp.StatusCode == http.StatusConflict:
+		return nil
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return ErrQueued
+	default:
+		return fmt.Errorf("leaderboard rejected the result: %v", resp.Status)
+	}
+}
+
+func (c *Client) load() ([]Submission, error) {
+	var queue []Submission
+
+	data, err := os.ReadFile(c.queue)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("error reading leaderboard queue: %v", err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &queue); err != nil {
+			return nil, fmt.Errorf("error decoding leaderboard queue: %v", err)
+		}
+	}
+
+	return queue, nil
+}
+
+func (c *Client) save(queue []Submission) error {
+	if len(queue) == 0 {
+		if err := os.Remove(c.queue); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("error removing leaderboard queue: %v", err)
+		}
+		return nil
+	}
+
+	data, err := json.Marshal(queue)
+	if err != nil {
+		return fmt.Errorf("error encoding leaderboard queue: %v", err)
+	}
+	if err := status.WriteFile(c.queue, data); err != nil {
+		return fmt.Errorf("error writing leaderboard queue: %v", err)
+	}
+
+	return nil
+}
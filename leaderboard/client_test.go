@@ -0,0 +1,108 @@
+package leaderboard
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Alvaroalonsobabbel/wordle/wordle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientPost(t *testing.T) {
+	game := &wordle.Status{Wordle: "HELLO", PuzzleNumber: 1234}
+	assert.NoError(t, game.Try("CELLO"))
+	assert.NoError(t, game.Try("HELLO"))
+
+	newClient := func(t *testing.T, url string) (*Client, string) {
+		queue := filepath.Join(t.TempDir(), "queue")
+		c, err := NewClient(url, "alice", queue)
+		require.NoError(t, err)
+		return c, queue
+	}
+
+	t.Run("posts to the leaderboard", func(t *testing.T) {
+		s, _ := newTestServer(t)
+		ts := httptest.NewServer(s)
+		defer ts.Close()
+		c, queue := newClient(t, ts.URL)
+
+		assert.NoError(t, c.Post(game))
+		assert.Len(t, s.store.all(), 1)
+		assert.Equal(t, "alice", s.store.all()[0].Player)
+		assert.NoFileExists(t, queue)
+	})
+
+	t.Run("posting twice is not an error", func(t *testing.T) {
+		s, _ := newTestServer(t)
+		ts := httptest.NewServer(s)
+		defer ts.Close()
+		c, _ := newClient(t, ts.URL)
+
+		assert.NoError(t, c.Post(game))
+		assert.NoError(t, c.Post(game))
+		assert.Len(t, s.store.all(), 1)
+	})
+
+	t.Run("when offline the result is queued and posted on the next flush", func(t *testing.T) {
+		s, _ := newTestServer(t)
+		offline := httptest.NewServer(s)
+		offline.Close()
+		c, queue := newClient(t, offline.URL)
+
+		assert.ErrorIs(t, c.Post(game), ErrQueued)
+		assert.ErrorIs(t, c.Post(game), ErrQueued)
+		assert.FileExists(t, queue)
+		queued, err := c.load()
+		assert.NoError(t, err)
+		assert.Len(t, queued, 1)
+
+		ts := httptest.NewServer(s)
+		defer ts.Close()
+		c, err = NewClient(ts.URL, "alice", queue)
+		assert.NoError(t, err)
+		assert.NoError(t, c.Flush())
+		assert.Len(t, s.store.all(), 1)
+		assert.NoFileExists(t, queue)
+	})
+
+	t.Run("server errors are retried", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer ts.Close()
+		c, queue := newClient(t, ts.URL)
+
+		assert.ErrorIs(t, c.Post(game), ErrQueued)
+		assert.FileExists(t, queue)
+	})
+
+	t.Run("rejected results are dropped", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer ts.Close()
+		c, queue := newClient(t, ts.URL)
+
+		err := c.Post(game)
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, ErrQueued)
+		_, err = os.Stat(queue)
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("errors of other queued results are not returned", func(t *testing.T) {
+		s, _ := newTestServer(t)
+		ts := httptest.NewServer(s)
+		defer ts.Close()
+		c, queue := newClient(t, ts.URL)
+		require.NoError(t, c.save([]Submission{{Player: "alice", PuzzleNumber: -1}}))
+
+		assert.NoError(t, c.Post(game))
+		assert.Len(t, s.store.all(), 1)
+		assert.NoFileExists(t, queue)
+	})
+}
@@ -0,0 +1,100 @@
+package leaderboard
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// Result is a finished game submitted by a player.
+type Result struct {
+	Player       string    `json:"player"`
+	PuzzleNumber int       `json:"puzzle_number"`
+	Attempts     int       `json:"attempts"` // 0 when the game was lost
+	HardMode     bool      `json:"hard_mode"`
+	Grid         []string  `json:"grid"`
+	Submitted    time.Time `json:"submitted"`
+}
+
+func (r Result) Won() bool {
+	return r.Attempts > 0
+}
+
+// Standing is a player's all-time record.
+type Standing struct {
+	Player       string  `json:"player"`
+	Played       int     `json:"played"`
+	Won          int     `json:"won"`
+	Average      float64 `json:"average"` // average attempts of the won games
+	Distribution [6]int  `json:"distribution"`
+}
+
+// Daily returns the results of a puzzle ranked by attempts, lost games
+// last and ties broken by who submitted first.
+func Daily(results []Result, puzzleNumber int) []Result {
+	var daily []Result
+	for _, r := range results {
+		if r.PuzzleNumber == puzzleNumber {
+			daily = append(daily, r)
+		}
+	}
+
+	slices.SortStableFunc(daily, func(a, b Result) int {
+		if a.Won() != b.Won() {
+			if a.Won() {
+				return -1
+			}
+			return 1
+		}
+
+		return cmp.Or(cmp.Compare(a.Attempts, b.Attempts), a.Submitted.Compare(b.Submitted))
+	})
+
+	return daily
+}
+
+// AllTime returns every player's standing ranked by games
+// won and then by the average attempts it took them.
+func AllTime(results []Result) []Standing {
+	var (
+		standings []Standing
+		index     = make(map[string]int)
+	)
+
+	for _, r := range results {
+		i, ok := index[r.Player]
+		if !ok {
+			i = len(standings)
+			index[r.Player] = i
+			standings = append(standings, Standing{Player: r.Player})
+		}
+
+		s := &standings[i]
+		s.Played++
+		if r.Won() {
+			s.Average = (s.Average*float64(s.Won) + float64(r.Attempts)) / float64(s.Won+1)
+			s.Won++
+			s.Distribution[r.Attempts-1]++
+		}
+	}
+
+	slices.SortFunc(standings, func(a, b Standing) int {
+		return cmp.Or(
+			cmp.Compare(b.Won, a.Won),
+			cmp.Compare(a.Average, b.Average),
+			cmp.Compare(a.Player, b.Player),
+		)
+	})
+
+	return standings
+}
+
+// Latest returns the highest puzzle number submitted.
+func Latest(results []Result) int {
+	var latest int
+	for _, r := range results {
+		latest = max(latest, r.PuzzleNumber)
+	}
+
+	return latest
+}
@@ -0,0 +1,202 @@
+package leaderboard
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Alvaroalonsobabbel/wordle/wordle"
+)
+
+const maxBodySize = 1 << 16
+
+//go:embed leaderboard.html
+var page string
+
+// Submission is the JSON body accepted by POST /api/results. Either
+// Share, the text from wordle.Status.Share, or Results, the JSON of
+// wordle.Status.Results, must be set.
+type Submission struct {
+	Player       string           `json:"player"`
+	PuzzleNumber int              `json:"puzzle_number"`
+	HardMode     bool             `json:"hard_mode"`
+	Share        string           `json:"share,omitempty"`
+	Results      [][]map[rune]int `json:"results,omitempty"`
+}
+
+type Server struct {
+	store *store
+	page  *template.Template
+	mux   *http.ServeMux
+	now   func() time.Time
+}
+
+// NewServer returns the leaderboard HTTP handler storing
+// the submitted results in the JSON file at path.
+func NewServer(path string) (*Server, error) {
+	store, err := newStore(path)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		store: store,
+		page:  template.Must(template.New("leaderboard").Funcs(template.FuncMap{"inc": func(i int) int { return i + 1 }}).Parse(page)),
+		mux:   http.NewServeMux(),
+		now:   time.Now,
+	}
+	s.mux.HandleFunc("POST /api/results", s.postResult)
+	s.mux.HandleFunc("GET /api/leaderboard/daily", s.getDaily)
+	s.mux.HandleFunc("GET /api/leaderboard", s.getAllTime)
+	s.mux.HandleFunc("GET /{$}", s.getPage)
+
+	return s, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// postResult accepts a Submission as JSON or, with any other content
+// type, the plain Share text with the player name in the query string.
+func (s *Server) postResult(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		http.Error(w, "unable to read request body", http.StatusBadRequest)
+		return
+	}
+
+	var sub Submission
+	if mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mt == "application/json" {
+		if err := json.Unmarshal(body, &sub); err != nil {
+			http.Error(w, "unable to decode request body", http.StatusBadRequest)
+			return
+		}
+	} else {
+		sub = Submission{Player: r.URL.Query().Get("player"), Share: string(body)}
+	}
+
+	result, err := sub.result()
+	if err == nil {
+		err = s.checkPuzzle(result.PuzzleNumber)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	result.Submitted = s.now()
+
+	if err := s.store.add(result); err != nil {
+		if errors.Is(err, ErrDuplicate) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		log.Printf("error storing result: %v", err)
+		http.Error(w, "unable to store result", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, result)
+}
+
+func (s *Server) getDaily(w http.ResponseWriter, r *http.Request) {
+	puzzle, err := s.puzzle(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, Daily(s.store.all(), puzzle))
+}
+
+func (s *Server) getAllTime(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, AllTime(s.store.all()))
+}
+
+func (s *Server) getPage(w http.ResponseWriter, r *http.Request) {
+	puzzle, err := s.puzzle(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	results := s.store.all()
+	data := struct {
+		Puzzle  int
+		Daily   []Result
+		AllTime []Standing
+	}{puzzle, Daily(results, puzzle), AllTime(results)}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := s.page.Execute(w, data); err != nil {
+		log.Printf("error rendering leaderboard: %v", err)
+	}
+}
+
+// puzzle returns the puzzle requested in the query string
+// or the latest puzzle submitted when there is none.
+func (s *Server) puzzle(r *http.Request) (int, error) {
+	p := r.URL.Query().Get("puzzle")
+	if p == "" {
+		return Latest(s.store.all()), nil
+	}
+
+	puzzle, err := strconv.Atoi(p)
+	if err != nil {
+		return 0, fmt.Errorf("invalid puzzle number: %s", p)
+	}
+
+	return puzzle, nil
+}
+
+// checkPuzzle rejects puzzles that haven't been published yet. Players a
+// timezone ahead may already be playing tomorrow's puzzle.
+func (s *Server) checkPuzzle(n int) error {
+	if n < 0 || n > wordle.PuzzleOn(s.now())+1 {
+		return fmt.Errorf("%w: puzzle number %d", ErrInvalidResult, n)
+	}
+
+	return nil
+}
+
+func (sub Submission) result() (Result, error) {
+	var (
+		r   Result
+		err error
+	)
+
+	player := strings.TrimSpace(sub.Player)
+	if player == "" {
+		return r, fmt.Errorf("%w: missing player", ErrInvalidResult)
+	}
+
+	switch {
+	case sub.Share != "":
+		r, err = parseShare(sub.Share)
+	case sub.Results != nil:
+		r, err = fromResults(sub.Results)
+		r.PuzzleNumber, r.HardMode = sub.PuzzleNumber, sub.HardMode
+	default:
+		err = fmt.Errorf("%w: missing share or results", ErrInvalidResult)
+	}
+	r.Player = player
+
+	return r, err
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("error encoding response: %v", err)
+	}
+}
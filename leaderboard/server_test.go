@@ -0,0 +1,199 @@
+package leaderboard
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Alvaroalonsobabbel/wordle/wordle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T) (*Server, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "leaderboard.json")
+	s, err := NewServer(path)
+	require.NoError(t, err)
+
+	clock := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.now = func() time.Time {
+		clock = clock.Add(time.Minute)
+		return clock
+	}
+
+	return s, path
+}
+
+func post(t *testing.T, s http.Handler, contentType, query, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/api/results"+query, strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func get(t *testing.T, s http.Handler, path string, v any) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	if v != nil {
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(v))
+	}
+
+	return rec
+}
+
+func TestPostResult(t *testing.T) {
+	game := &wordle.Status{Wordle: "HELLO", PuzzleNumber: 1234, HardMode: true}
+	assert.NoError(t, game.Try("CELLO"))
+	assert.NoError(t, game.Try("HELLO"))
+
+	t.Run("share text", func(t *testing.T) {
+		s, _ := newTestServer(t)
+		rec := post(t, s, "text/plain", "?player=alice", game.Share())
+		assert.Equal(t, http.StatusCreated, rec.Code)
+
+		var got Result
+		assert.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+		assert.Equal(t, "alice", got.Player)
+		assert.Equal(t, 1234, got.PuzzleNumber)
+		assert.Equal(t, 2, got.Attempts)
+		assert.True(t, got.HardMode)
+		assert.Equal(t, []string{"⬜🟩🟩🟩🟩", "🟩🟩🟩🟩🟩"}, got.Grid)
+	})
+
+	t.Run("JSON with the status results", func(t *testing.T) {
+		s, _ := newTestServer(t)
+		body, err := json.Marshal(Submission{Player: "bob", PuzzleNumber: 1234, Results: game.Results})
+		assert.NoError(t, err)
+
+		rec := post(t, s, "application/json", "", string(body))
+		assert.Equal(t, http.StatusCreated, rec.Code)
+
+		var got Result
+		assert.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+		assert.Equal(t, "bob", got.Player)
+		assert.Equal(t, 2, got.Attempts)
+	})
+
+	t.Run("JSON with the share text", func(t *testing.T) {
+		s, _ := newTestServer(t)
+		body, err := json.Marshal(Submission{Player: "carol", Share: game.Share()})
+		assert.NoError(t, err)
+
+		rec := post(t, s, "application/json; charset=utf-8", "", string(body))
+		assert.Equal(t, http.StatusCreated, rec.Code)
+	})
+
+	t.Run("the same player can't submit a puzzle twice", func(t *testing.T) {
+		s, _ := newTestServer(t)
+		assert.Equal(t, http.StatusCreated, post(t, s, "text/plain", "?player=alice", game.Share()).Code)
+		assert.Equal(t, http.StatusConflict, post(t, s, "text/plain", "?player=alice", game.Share()).Code)
+		assert.Equal(t, http.StatusConflict, post(t, s, "text/plain", "?player=Alice", game.Share()).Code, "names are case insensitive")
+	})
+
+	t.Run("invalid submissions", func(t *testing.T) {
+		s, _ := newTestServer(t)
+		results, err := json.Marshal(game.Results)
+		require.NoError(t, err)
+		tests := map[string]struct{ contentType, query, body string }{
+			"future puzzle":          {"text/plain", "?player=alice", "Wordle 9,999 1/6\n🟩🟩🟩🟩🟩"},
+			"future puzzle results":  {"application/json", "", `{"player":"alice","puzzle_number":9999,"results":` + string(results) + `}`},
+			"negative puzzle number": {"application/json", "", `{"player":"alice","puzzle_number":-1,"results":` + string(results) + `}`},
+			"missing player":         {"text/plain", "", game.Share()},
+			"not a result":           {"text/plain", "?player=alice", "hello"},
+			"bad JSON":               {"application/json", "", "{"},
+			"empty JSON":             {"application/json", "", `{"player":"alice"}`},
+			"fake score":             {"text/plain", "?player=alice", "Wordle 1 1/6\n⬜🟩🟩🟩🟩"},
+		}
+		for name, tt := range tests {
+			t.Run(name, func(t *testing.T) {
+				assert.Equal(t, http.StatusBadRequest, post(t, s, tt.contentType, tt.query, tt.body).Code)
+			})
+		}
+	})
+
+	t.Run("results persist in the file", func(t *testing.T) {
+		s, path := newTestServer(t)
+		assert.Equal(t, http.StatusCreated, post(t, s, "text/plain", "?player=alice", game.Share()).Code)
+
+		tmp, err := filepath.Glob(path + ".*.tmp")
+		assert.NoError(t, err)
+		assert.Empty(t, tmp, "the file is replaced in a single step")
+
+		reopened, err := NewServer(path)
+		assert.NoError(t, err)
+		var got []Result
+		get(t, reopened, "/api/leaderboard/daily", &got)
+		assert.Len(t, got, 1)
+	})
+}
+
+func TestLeaderboards(t *testing.T) {
+	s, _ := newTestServer(t)
+	submissions := []struct{ player, share string }{
+		{"alice", "Wordle 1 3/6\n⬜⬜⬜⬜⬜\n🟨🟩⬜⬜⬜\n🟩🟩🟩🟩🟩"},
+		{"bob", "Wordle 1 X/6\n" + strings.Repeat("⬜⬜⬜⬜⬜\n", 6)},
+		{"carol", "Wordle 1 2/6\n🟨🟩⬜⬜⬜\n🟩🟩🟩🟩🟩"},
+		{"dave", "Wordle 1 3/6\n⬜⬜⬜⬜⬜\n🟨🟩⬜⬜⬜\n🟩🟩🟩🟩🟩"},
+		{"alice", "Wordle 2 2/6\n⬜⬜⬜⬜⬜\n🟩🟩🟩🟩🟩"},
+		{"bob", "Wordle 2 4/6*\n⬜⬜⬜⬜⬜\n⬜⬜⬜⬜⬜\n⬜⬜⬜⬜⬜\n🟩🟩🟩🟩🟩"},
+	}
+	for _, sub := range submissions {
+		assert.Equal(t, http.StatusCreated, post(t, s, "text/plain", "?player="+sub.player, sub.share).Code)
+	}
+
+	t.Run("daily defaults to the latest puzzle", func(t *testing.T) {
+		var got []Result
+		get(t, s, "/api/leaderboard/daily", &got)
+		assert.Len(t, got, 2)
+		assert.Equal(t, "alice", got[0].Player)
+		assert.Equal(t, "bob", got[1].Player)
+	})
+
+	t.Run("daily ranks by attempts, then submission time, lost games last", func(t *testing.T) {
+		var got []Result
+		get(t, s, "/api/leaderboard/daily?puzzle=1", &got)
+		var players []string
+		for _, r := range got {
+			players = append(players, r.Player)
+		}
+		assert.Equal(t, []string{"carol", "alice", "dave", "bob"}, players)
+	})
+
+	t.Run("daily with an invalid puzzle", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, get(t, s, "/api/leaderboard/daily?puzzle=abc", nil).Code)
+	})
+
+	t.Run("all time ranks by wins and then by average", func(t *testing.T) {
+		var got []Standing
+		get(t, s, "/api/leaderboard", &got)
+		assert.Equal(t, []Standing{
+			{Player: "alice", Played: 2, Won: 2, Average: 2.5, Distribution: [6]int{0, 1, 1}},
+			{Player: "carol", Played: 1, Won: 1, Average: 2, Distribution: [6]int{0, 1}},
+			{Player: "dave", Played: 1, Won: 1, Average: 3, Distribution: [6]int{0, 0, 1}},
+			{Player: "bob", Played: 2, Won: 1, Average: 4, Distribution: [6]int{0, 0, 0, 1}},
+		}, got)
+	})
+
+	t.Run("HTML page", func(t *testing.T) {
+		rec := get(t, s, "/?puzzle=1", nil)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Header().Get("Content-Type"), "text/html")
+		body := rec.Body.String()
+		assert.Contains(t, body, "Wordle 1")
+		assert.Less(t, strings.Index(body, "carol"), strings.Index(body, "dave"))
+		assert.Contains(t, body, "X/6")
+	})
+
+	t.Run("unknown path", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, get(t, s, "/nope", nil).Code)
+	})
+}
@@ -0,0 +1,45 @@
+package leaderboard
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/Alvaroalonsobabbel/wordle/wordle"
+)
+
+var ErrInvalidResult = errors.New("invalid result")
+
+// parseShare reads the text produced by wordle.Status.Share.
+func parseShare(text string) (Result, error) {
+	shared, err := wordle.ParseShare(text)
+	if err != nil {
+		return Result{}, fmt.Errorf("%w: %v", ErrInvalidResult, err)
+	}
+
+	return Result{PuzzleNumber: shared.PuzzleNumber, Attempts: shared.Guesses, HardMode: shared.HardMode, Grid: shared.Grid()}, nil
+}
+
+// fromResults builds a Result out of the wordle.Status.Results of a finished game.
+func fromResults(results [][]map[rune]int) (Result, error) {
+	var shared wordle.Shared
+	for _, res := range results {
+		var row []int
+		for _, stat := range res {
+			for _, v := range stat {
+				row = append(row, v)
+			}
+		}
+		shared.Rows = append(shared.Rows, row)
+	}
+
+	rows := shared.Rows
+	if len(rows) > 0 && !slices.ContainsFunc(rows[len(rows)-1], func(v int) bool { return v != wordle.Correct }) {
+		shared.Guesses = len(rows)
+	}
+	if err := shared.Validate(); err != nil {
+		return Result{}, fmt.Errorf("%w: %v", ErrInvalidResult, err)
+	}
+
+	return Result{Attempts: shared.Guesses, Grid: shared.Grid()}, nil
+}
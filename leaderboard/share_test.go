@@ -0,0 +1,78 @@
+package leaderboard
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Alvaroalonsobabbel/wordle/wordle"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseShare(t *testing.T) {
+	tests := []struct {
+		name    string
+		share   string
+		want    Result
+		wantErr bool
+	}{
+		{
+			name:  "win",
+			share: "Wordle 1,234 2/6*\n⬜️🟨⬛🟩🟩\n🟩🟩🟩🟩🟩",
+			want:  Result{PuzzleNumber: 1234, Attempts: 2, HardMode: true, Grid: []string{"⬜🟨⬜🟩🟩", "🟩🟩🟩🟩🟩"}},
+		},
+		{
+			name:  "loss",
+			share: "Wordle 12 X/6\n" + strings.Repeat("⬜⬜⬜⬜⬜\n", 6),
+			want:  Result{PuzzleNumber: 12, Grid: slices.Repeat([]string{"⬜⬜⬜⬜⬜"}, 6)},
+		},
+		{
+			name:  "high contrast",
+			share: "Wordle 12 2/6\n🟦⬜🟧⬜⬜\n🟧🟧🟧🟧🟧",
+			want:  Result{PuzzleNumber: 12, Attempts: 2, Grid: []string{"🟨⬜🟩⬜⬜", "🟩🟩🟩🟩🟩"}},
+		},
+		{
+			name:  "with a verification token",
+			share: "Wordle 12 1/6\n🟩🟩🟩🟩🟩\n#abcdef12",
+			want:  Result{PuzzleNumber: 12, Attempts: 1, Grid: []string{"🟩🟩🟩🟩🟩"}},
+		},
+		{name: "bad header", share: "Wordle 12 7/6\n🟩🟩🟩🟩🟩", wantErr: true},
+		{name: "no grid", share: "Wordle 12 1/6", wantErr: true},
+		{name: "short row", share: "Wordle 12 1/6\n🟩🟩🟩🟩", wantErr: true},
+		{name: "score doesn't match the rows", share: "Wordle 12 2/6\n🟩🟩🟩🟩🟩", wantErr: true},
+		{name: "solved before the last row", share: "Wordle 12 2/6\n🟩🟩🟩🟩🟩\n🟩🟩🟩🟩🟩", wantErr: true},
+		{name: "lost with less than 6 rows", share: "Wordle 12 X/6\n⬜⬜⬜⬜⬜", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := parseShare(test.share)
+			if test.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidResult)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestFromResults(t *testing.T) {
+	t.Run("won", func(t *testing.T) {
+		game := &wordle.Status{Wordle: "HELLO"}
+		assert.NoError(t, game.Try("CELLO"))
+		assert.NoError(t, game.Try("HELLO"))
+
+		got, err := fromResults(game.Results)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, got.Attempts)
+	})
+
+	t.Run("game in progress", func(t *testing.T) {
+		game := &wordle.Status{Wordle: "HELLO"}
+		assert.NoError(t, game.Try("CELLO"))
+
+		_, err := fromResults(game.Results)
+		assert.ErrorIs(t, err, ErrInvalidResult)
+	})
+}
@@ -0,0 +1,71 @@
+package leaderboard
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/Alvaroalonsobabbel/wordle/status"
+)
+
+var ErrDuplicate = errors.New("result already submitted")
+
+// store keeps the submitted results in a local JSON file.
+type store struct {
+	mu      sync.RWMutex
+	path    string
+	results []Result
+}
+
+func newStore(path string) (*store, error) {
+	s := &store{path: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("error reading leaderboard file: %v", err)
+	}
+	if len(data) == 0 {
+		return s, nil
+	}
+	if err := json.Unmarshal(data, &s.results); err != nil {
+		return nil, fmt.Errorf("error decoding leaderboard file: %v", err)
+	}
+
+	return s, nil
+}
+
+func (s *store) add(r Result) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, v := range s.results {
+		if strings.EqualFold(v.Player, r.Player) && v.PuzzleNumber == r.PuzzleNumber {
+			return ErrDuplicate
+		}
+	}
+
+	data, err := json.Marshal(append(s.results, r))
+	if err != nil {
+		return fmt.Errorf("error encoding leaderboard: %v", err)
+	}
+	if err := status.WriteFile(s.path, data); err != nil {
+		return fmt.Errorf("error writing leaderboard file: %v", err)
+	}
+	s.results = append(s.results, r)
+
+	return nil
+}
+
+func (s *store) all() []Result {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return slices.Clone(s.results)
+}
@@ -1,13 +1,27 @@
 package main
 
 import (
+	"bufio"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
+	"net"
+	"net/http"
 	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/Alvaroalonsobabbel/wordle/config"
+	"github.com/Alvaroalonsobabbel/wordle/leaderboard"
+	"github.com/Alvaroalonsobabbel/wordle/race"
+	"github.com/Alvaroalonsobabbel/wordle/sshserver"
 	"github.com/Alvaroalonsobabbel/wordle/status"
 	"github.com/Alvaroalonsobabbel/wordle/terminal"
+	"github.com/Alvaroalonsobabbel/wordle/theme"
 	"github.com/Alvaroalonsobabbel/wordle/wordle"
 )
 
@@ -17,33 +31,583 @@ const (
 	hardModeFlag     = "hard"
 	versionFlag      = "version"
 	removeStatusFlag = "rmstatus"
+	tokenFlag        = "token"
+	themeFlag        = "theme"
+	accessibleFlag   = "accessible"
+	motionFlag       = "motion"
+	dataDirFlag      = "data-dir"
+	profileFlag      = "profile"
+
+	verifyCmd  = "verify"
+	serveCmd   = "serve"
+	hostCmd    = "host"
+	joinCmd    = "join"
+	sshCmd     = "ssh-serve"
+	profileCmd = "profile"
+	statsCmd   = "stats"
+	exportCmd  = "export"
+	importCmd  = "import"
+
+	sshDir     = ".wordle_ssh"
+	sshHostKey = "host_key"
+
+	// takeOverTimeout is how long the instance playing the game has to
+	// save it and quit when it's taken over.
+	takeOverTimeout = 5 * time.Second
 )
 
-var hardMode bool
+var (
+	hardMode, shareToken, accessible, rmStatus bool
+	themeName, motion, dataDir, profile        string
+)
 
 func main() {
 	evalOptions()
+	if rmStatus {
+		removeStatus()
+		return
+	}
+
+	switch flag.Arg(0) {
+	case verifyCmd:
+		verify()
+		return
+	case serveCmd:
+		serve(flag.Args()[1:])
+		return
+	case hostCmd:
+		host(flag.Args()[1:])
+		return
+	case joinCmd:
+		join(flag.Args()[1:])
+		return
+	case sshCmd:
+		sshServe(flag.Args()[1:])
+		return
+	case profileCmd:
+		profiles(flag.Args()[1:])
+		return
+	case statsCmd:
+		stats()
+		return
+	case exportCmd:
+		export(flag.Args()[1:])
+		return
+	case importCmd:
+		importHistory(flag.Args()[1:])
+		return
+	}
+
+	dir := profileDir()
+	unlock, saving := lockGame(dir)
+	store := openStore(dir)
+	saved, err := store.LoadGame()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	conf := []wordle.ConfigSetter{wordle.WithSavedWordle(saved)}
+	if shareToken {
+		conf = append(conf, wordle.WithShareToken(key(dir)))
+	}
+
+	tc := termConfig(dir)
+	if saving {
+		tc = append(tc, terminal.WithSaver(store))
+	} else {
+		tc = append(tc, terminal.WithSaver(nil))
+	}
+	err = terminal.New(wordle.NewGame(hardMode, conf...), tc...).Start()
+	if cerr := store.Close(); err == nil {
+		err = cerr
+	}
+	unlock()
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// lockGame keeps other instances from saving the game of dir while it's
+// played. When another one is playing it, the player chooses to take it
+// over or to play without saving.
+func lockGame(dir string) (unlock func(), saving bool) {
+	l, err := status.Lock(dir)
+	if errors.Is(err, status.ErrLocked) {
+		fmt.Printf("Heads up, %v.\n(t)ake over, play (r)ead-only or (q)uit? ", err)
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n') //nolint: errcheck
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "t":
+			l, err = status.TakeOver(dir, takeOverTimeout)
+		case "r":
+			return func() {}, false
+		default:
+			os.Exit(0)
+		}
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return func() { l.Unlock() }, true //nolint: errcheck
+}
+
+func termConfig(dir string) []terminal.ConfigSetter {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	conf := []terminal.ConfigSetter{withTheme(cfg), withAnimation(cfg)}
+	if cfg.Share.Squares == "dark" {
+		conf = append(conf, terminal.WithDarkSquares())
+	}
+	if accessible {
+		conf = append(conf, terminal.WithAccessible())
+	}
+	if cfg.Leaderboard.URL == "" {
+		return conf
+	}
+
+	client, err := leaderboard.NewClient(cfg.Leaderboard.URL, cfg.Leaderboard.Player, filepath.Join(dir, status.QueueFile))
+	if err != nil {
+		log.Fatal(err)
+	}
+	// Games finished while offline are posted in the background.
+	go client.Flush() //nolint: errcheck
 
-	status, err := status.Game().Load()
+	return append(conf, terminal.WithPoster(client))
+}
+
+// withTheme displays the game with the theme chosen with the flag or in the config file.
+func withTheme(cfg *config.Config) terminal.ConfigSetter {
+	name := themeName
+	if name == "" {
+		name = cfg.Theme
+	}
+	th, err := theme.Get(name, cfg.Themes)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	terminal.New(wordle.NewGame(hardMode, wordle.WithSavedWordle(status))).Start()
+	return terminal.WithTheme(th)
+}
+
+// withAnimation animates the game as set in the config file, the motion flag takes precedence.
+func withAnimation(cfg *config.Config) terminal.ConfigSetter {
+	m := terminal.Motion(cfg.Animation.Motion)
+	switch motion {
+	case "":
+	case string(terminal.MotionFull), string(terminal.MotionReduced), string(terminal.MotionOff):
+		m = terminal.Motion(motion)
+	default:
+		log.Fatalf("invalid -%s %q, choose one of full, reduced or off", motionFlag, motion)
+	}
+
+	return terminal.WithAnimation(terminal.Animation{Speed: cfg.Animation.Speed, Motion: m})
 }
 
 func evalOptions() {
 	flag.BoolVar(&hardMode, hardModeFlag, false, "Sets the Game to Hard Mode")
+	flag.BoolVar(&shareToken, tokenFlag, false, "Appends a verification token to the shared result")
+	flag.BoolVar(&accessible, accessibleFlag, false, "Plays one line at a time with plain text for screen readers")
+	flag.StringVar(&motion, motionFlag, "", "Sets how much the game moves: full, reduced or off")
+	flag.StringVar(&themeName, themeFlag, "", "Displays the game with a theme: "+strings.Join(theme.Names(), ", ")+" or one from the config file")
 	flag.BoolFunc(versionFlag, "Prints version", version)
-	flag.BoolFunc(removeStatusFlag, "Deletes the status file", status.Remove)
+	flag.StringVar(&dataDir, dataDirFlag, "", "Directory where the game is stored, defaults to $WORDLE_HOME or the user data directory")
+	flag.StringVar(&profile, profileFlag, status.DefaultProfile, "Plays as the given profile, see '"+profileCmd+" -h'")
+	flag.BoolVar(&rmStatus, removeStatusFlag, false, "Deletes the status file")
+	flag.Usage = usage
 	if err := flag.CommandLine.Parse(os.Args[1:]); err != nil {
 		log.Fatal(err)
 	}
 }
 
+func usage() {
+	fmt.Fprintf(flag.CommandLine.Output(), "Usage: wordle [options] [command]\n\nCommands:\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "  %s\tverifies a shared result read from stdin against the saved game\n", verifyCmd)
+	fmt.Fprintf(flag.CommandLine.Output(), "  %s\truns the team leaderboard server, see '%[1]s -h'\n", serveCmd)
+	fmt.Fprintf(flag.CommandLine.Output(), "  %s\thosts a head-to-head race, see '%[1]s -h'\n", hostCmd)
+	fmt.Fprintf(flag.CommandLine.Output(), "  %s\tjoins the race hosted at host:port, see '%[1]s -h'\n", joinCmd)
+	fmt.Fprintf(flag.CommandLine.Output(), "  %s\thosts the game over SSH, see '%[1]s -h'\n", sshCmd)
+	fmt.Fprintf(flag.CommandLine.Output(), "  %s\tmanages the profiles of the players sharing this computer, see '%[1]s -h'\n", profileCmd)
+	fmt.Fprintf(flag.CommandLine.Output(), "  %s\tprints the statistics of the profile\n", statsCmd)
+	fmt.Fprintf(flag.CommandLine.Output(), "  %s\twrites the history of the profile as csv or json, see '%[1]s -h'\n", exportCmd)
+	fmt.Fprintf(flag.CommandLine.Output(), "  %s\tadds exported games or pasted results to the history, see '%[1]s -h'\n\nOptions:\n", importCmd)
+	flag.PrintDefaults()
+}
+
 func version(string) error {
 	fmt.Println(VERSION)
 	os.Exit(0)
 
 	return nil
 }
+
+// resolveDataDir sets where the game is stored when the flag is not
+// given, and moves there the files older versions kept in the home directory.
+func resolveDataDir() {
+	if dataDir == "" {
+		dir, err := status.Dir()
+		if err != nil {
+			log.Fatal(err)
+		}
+		dataDir = dir
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return
+	}
+	if err := status.Migrate(home, dataDir); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// profileDir returns the directory of the profile chosen with the flag.
+func profileDir() string {
+	resolveDataDir()
+	dir, err := status.Profiles(dataDir, backend()).Dir(profile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return dir
+}
+
+// backend returns the storage backend chosen in the config file.
+func backend() status.Backend {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	b, err := status.GetBackend(cfg.Storage)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return b
+}
+
+func openStore(dir string) status.Store {
+	store, err := backend()(dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return store
+}
+
+// withStore runs f with the store of dir, closing it afterwards.
+func withStore(dir string, f func(status.Store) error) {
+	store := openStore(dir)
+	err := f(store)
+	if cerr := store.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func removeStatus() {
+	dir := profileDir()
+	l, err := status.Lock(dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	withStore(dir, status.Store.RemoveGame)
+	l.Unlock() //nolint: errcheck
+
+	fmt.Println("Status file removed.")
+}
+
+func key(dir string) []byte {
+	key, err := status.InDir(dir).Key()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return key
+}
+
+func verify() {
+	dir := profileDir()
+	var saved *wordle.Status
+	withStore(dir, func(s status.Store) (err error) {
+		saved, err = s.LoadGame()
+		return err
+	})
+	if saved == nil {
+		log.Fatal(wordle.ErrNotFinished)
+	}
+
+	paste, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		log.Fatalf("error reading the result: %v", err)
+	}
+	if err := saved.VerifyShare(string(paste), key(dir)); err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println("Result verified.")
+}
+
+func profiles(args []string) {
+	fs := flag.NewFlagSet(profileCmd, flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: wordle %s <command>\n\nCommands:\n", profileCmd)
+		fmt.Fprintf(fs.Output(), "  list\t\t\tlists the profiles\n")
+		fmt.Fprintf(fs.Output(), "  create <name>\t\tcreates a profile, play with it with 'wordle -%s <name>'\n", profileFlag)
+		fmt.Fprintf(fs.Output(), "  rename <old> <new>\trenames a profile\n")
+		fmt.Fprintf(fs.Output(), "  delete <name>\t\tdeletes a profile with its games\n")
+		fmt.Fprintf(fs.Output(), "  merge <from> <into>\tadds the games of a profile to another one\n")
+	}
+	if err := fs.Parse(args); err != nil {
+		log.Fatal(err)
+	}
+
+	resolveDataDir()
+	p := status.Profiles(dataDir, backend())
+	var err error
+	switch cmd, args := fs.Arg(0), fs.Args(); {
+	case cmd == "list" && len(args) == 1:
+		var names []string
+		if names, err = p.List(); err == nil {
+			fmt.Println(strings.Join(names, "\n"))
+		}
+	case cmd == "create" && len(args) == 2:
+		err = p.Create(args[1])
+	case cmd == "rename" && len(args) == 3:
+		err = p.Rename(args[1], args[2])
+	case cmd == "delete" && len(args) == 2:
+		err = p.Delete(args[1])
+	case cmd == "merge" && len(args) == 3:
+		err = p.Merge(args[1], args[2])
+	default:
+		fs.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func stats() {
+	var st status.Stats
+	withStore(profileDir(), func(s status.Store) (err error) {
+		st, err = s.Stats()
+		return err
+	})
+
+	winRate := 0
+	if st.Played > 0 {
+		winRate = st.Won * 100 / st.Played
+	}
+	fmt.Printf("Played: %d\nWin %%: %d\nCurrent streak: %d\nMax streak: %d\n\nGuess distribution:\n", st.Played, winRate, st.CurrentStreak, st.MaxStreak)
+	for i, n := range st.Guesses {
+		fmt.Printf("%d: %s %d\n", i+1, strings.Repeat("█", n), n)
+	}
+}
+
+func export(args []string) {
+	fs := flag.NewFlagSet(exportCmd, flag.ExitOnError)
+	format := fs.String("format", status.FormatCSV, "Format of the history: csv or json")
+	if err := fs.Parse(args); err != nil {
+		log.Fatal(err)
+	}
+
+	var records []status.Record
+	withStore(profileDir(), func(s status.Store) (err error) {
+		records, err = s.History()
+		return err
+	})
+	if err := status.Export(os.Stdout, records, *format); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func importHistory(args []string) {
+	fs := flag.NewFlagSet(importCmd, flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: wordle %s [file]\n\n", importCmd)
+		fmt.Fprintf(fs.Output(), "Adds to the history the games exported with '%s', or the results shared\n", exportCmd)
+		fmt.Fprintf(fs.Output(), "here or in the NYT game pasted one after the other. Reads stdin without a file.\n")
+	}
+	if err := fs.Parse(args); err != nil {
+		log.Fatal(err)
+	}
+
+	in := io.ReadCloser(os.Stdin)
+	switch fs.NArg() {
+	case 0:
+	case 1:
+		f, err := os.Open(fs.Arg(0))
+		if err != nil {
+			log.Fatal(err)
+		}
+		in = f
+	default:
+		fs.Usage()
+		os.Exit(2)
+	}
+	records, err := status.Import(in)
+	in.Close() //nolint: errcheck
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	dir := profileDir()
+	l, err := status.Lock(dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var added int
+	withStore(dir, func(s status.Store) error {
+		before, err := s.History()
+		if err != nil {
+			return err
+		}
+		if err := s.AddHistory(records...); err != nil {
+			return err
+		}
+		after, err := s.History()
+		added = len(after) - len(before)
+		return err
+	})
+	l.Unlock() //nolint: errcheck
+
+	fmt.Printf("Imported %d games, %d were already in the history.\n", added, len(records)-added)
+}
+
+func serve(args []string) {
+	fs := flag.NewFlagSet(serveCmd, flag.ExitOnError)
+	addr := fs.String("addr", ":8080", "Address to listen on")
+	file := fs.String("file", "leaderboard.json", "File where the results are stored")
+	if err := fs.Parse(args); err != nil {
+		log.Fatal(err)
+	}
+
+	server, err := leaderboard.NewServer(*file)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("Leaderboard listening on %s", *addr)
+	srv := &http.Server{Addr: *addr, Handler: server, ReadHeaderTimeout: 10 * time.Second}
+	log.Fatal(srv.ListenAndServe())
+}
+
+func host(args []string) {
+	fs := flag.NewFlagSet(hostCmd, flag.ExitOnError)
+	listen := fs.String("addr", ":7777", "Address to listen on")
+	name := fs.String("name", userName(), "Your name in the race")
+	if err := fs.Parse(args); err != nil {
+		log.Fatal(err)
+	}
+
+	l, err := net.Listen("tcp", *listen)
+	if err != nil {
+		log.Fatal(err)
+	}
+	h := race.NewHost(l, wordle.RandomAnswer())
+	defer h.Close()
+	go h.Serve() //nolint: errcheck
+
+	_, port, _ := net.SplitHostPort(l.Addr().String())
+	addr := net.JoinHostPort("localhost", port)
+	joined := make(chan *race.Client, 1)
+	go func() { joined <- joinRace(addr, *name) }()
+
+	fmt.Printf("Players can join with 'wordle %s <host>:%s', press Enter to start the race.\n", joinCmd, port)
+	if _, err := os.Stdin.Read(make([]byte, 1)); err != nil {
+		log.Fatal(err)
+	}
+	h.Start()
+
+	playRace(<-joined)
+}
+
+func join(args []string) {
+	fs := flag.NewFlagSet(joinCmd, flag.ExitOnError)
+	name := fs.String("name", userName(), "Your name in the race")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: wordle %s [options] host:port\n", joinCmd)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		log.Fatal(err)
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	fmt.Println("Waiting for the host to start the race.")
+	playRace(joinRace(fs.Arg(0), *name))
+}
+
+func joinRace(addr, name string) *race.Client {
+	client, err := race.Join(addr, name)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return client
+}
+
+func playRace(client *race.Client) {
+	defer client.Close()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Races are not saved so they don't overwrite the daily game.
+	conf := []terminal.ConfigSetter{terminal.WithRace(client), terminal.WithSaver(nil), withTheme(cfg), withAnimation(cfg)}
+	if cfg.Share.Squares == "dark" {
+		conf = append(conf, terminal.WithDarkSquares())
+	}
+	err = terminal.New(&wordle.Status{Wordle: client.Word, HardMode: hardMode}, conf...).Start()
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func userName() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+
+	return ""
+}
+
+func sshServe(args []string) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fs := flag.NewFlagSet(sshCmd, flag.ExitOnError)
+	addr := fs.String("addr", ":2222", "Address to listen on")
+	dir := fs.String("dir", filepath.Join(home, sshDir), "Directory where the host key and the players status are stored")
+	if err := fs.Parse(args); err != nil {
+		log.Fatal(err)
+	}
+
+	hostKey, err := sshserver.HostKey(filepath.Join(*dir, sshHostKey))
+	if err != nil {
+		log.Fatal(err)
+	}
+	server := sshserver.NewServer(hostKey, *dir, func(saved *wordle.Status) (*wordle.Status, error) {
+		return wordle.TodaysGame(hardMode, wordle.WithSavedWordle(saved))
+	})
+
+	l, err := net.Listen("tcp", *addr)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("SSH server listening on %s", *addr)
+	log.Fatal(server.Serve(l))
+}
@@ -0,0 +1,105 @@
+package race
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/Alvaroalonsobabbel/wordle/wordle"
+)
+
+// Client is a player's connection to a race.
+type Client struct {
+	Word string
+
+	name    string
+	mu      sync.Mutex
+	conn    net.Conn
+	enc     *json.Encoder
+	updates chan Update
+}
+
+// Join connects to the race hosted at addr and waits for the word to play.
+func Join(addr, name string) (*Client, error) {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("unable to join the race: %v", err)
+	}
+
+	c := &Client{
+		conn:    conn,
+		enc:     json.NewEncoder(conn),
+		updates: make(chan Update, 1),
+	}
+	if err := c.enc.Encode(message{Type: msgJoin, Player: name}); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("unable to join the race: %v", err)
+	}
+
+	var (
+		dec = json.NewDecoder(bufio.NewReader(conn))
+		msg message
+	)
+	if err := dec.Decode(&msg); err != nil || msg.Type != msgStart {
+		conn.Close()
+		return nil, fmt.Errorf("unable to start the race: %v", err)
+	}
+	c.name, c.Word = msg.Player, msg.Word
+
+	go c.listen(dec)
+
+	return c, nil
+}
+
+// Name is the player's name in the race, which might differ
+// from the one used to join if it was already taken.
+func (c *Client) Name() string {
+	return c.name
+}
+
+// Report sends the player's progress to the host.
+func (c *Client) Report(s *wordle.Status) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.enc.Encode(message{Type: msgProgress, Rows: rows(s.Results), Solved: solved(s)})
+}
+
+// Updates delivers the latest state of the race. Updates that
+// are not read before a newer one arrives are dropped.
+func (c *Client) Updates() <-chan Update {
+	return c.updates
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+func (c *Client) listen(dec *json.Decoder) {
+	defer close(c.updates)
+
+	for {
+		var msg message
+		if err := dec.Decode(&msg); err != nil {
+			return
+		}
+		if msg.Type != msgState {
+			continue
+		}
+
+		u := Update{Winner: msg.Winner}
+		for _, p := range msg.Players {
+			if p.Name != c.name {
+				u.Opponents = append(u.Opponents, p)
+			}
+		}
+
+		select {
+		case <-c.updates:
+		default:
+		}
+		c.updates <- u
+	}
+}
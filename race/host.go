@@ -0,0 +1,180 @@
+package race
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"sync"
+)
+
+// Host runs a race, keeping the state of every
+// player and broadcasting it on every change.
+type Host struct {
+	mu      sync.Mutex
+	l       net.Listener
+	word    string
+	players []*Player
+	conns   map[net.Conn]*json.Encoder
+	names   map[net.Conn]string
+	winner  string
+	started bool
+}
+
+func NewHost(l net.Listener, word string) *Host {
+	return &Host{
+		l:     l,
+		word:  word,
+		conns: make(map[net.Conn]*json.Encoder),
+		names: make(map[net.Conn]string),
+	}
+}
+
+// Start sends the word to every player that joined so far. Players
+// joining after the race started get the word straight away.
+func (h *Host) Start() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.started = true
+	for conn := range h.conns {
+		h.start(conn)
+	}
+	h.broadcast()
+}
+
+// Serve accepts players until the listener is closed.
+func (h *Host) Serve() error {
+	for {
+		conn, err := h.l.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+
+		go h.handle(conn)
+	}
+}
+
+func (h *Host) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for conn := range h.conns {
+		conn.Close()
+	}
+
+	return h.l.Close()
+}
+
+func (h *Host) handle(conn net.Conn) {
+	defer func() {
+		h.mu.Lock()
+		delete(h.conns, conn)
+		delete(h.names, conn)
+		h.mu.Unlock()
+		conn.Close()
+	}()
+
+	var (
+		dec = json.NewDecoder(bufio.NewReader(conn))
+		msg message
+	)
+	if err := dec.Decode(&msg); err != nil || msg.Type != msgJoin {
+		return
+	}
+
+	player := h.join(conn, msg.Player)
+
+	for {
+		var msg message
+		if err := dec.Decode(&msg); err != nil {
+			return
+		}
+		if msg.Type == msgProgress {
+			h.progress(player, msg)
+		}
+	}
+}
+
+func (h *Host) join(conn net.Conn, name string) *Player {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	player := &Player{Name: h.uniqueName(name)}
+	h.players = append(h.players, player)
+	h.conns[conn] = json.NewEncoder(conn)
+	h.names[conn] = player.Name
+
+	if h.started {
+		h.start(conn)
+		h.broadcast()
+	}
+
+	return player
+}
+
+// start must be called with the lock held.
+func (h *Host) start(conn net.Conn) {
+	if err := h.conns[conn].Encode(message{Type: msgStart, Player: h.names[conn], Word: h.word}); err != nil {
+		log.Printf("error starting race for %s: %v", h.names[conn], err)
+	}
+}
+
+// progress ignores updates claiming a result their rows don't show.
+func (h *Host) progress(player *Player, msg message) {
+	if msg.Solved != solvedRows(msg.Rows) {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	player.Rows = msg.Rows
+	player.Solved = msg.Solved
+	if player.Solved && h.winner == "" {
+		h.winner = player.Name
+	}
+	h.broadcast()
+}
+
+// broadcast must be called with the lock held.
+func (h *Host) broadcast() {
+	if !h.started {
+		return
+	}
+
+	msg := message{Type: msgState, Winner: h.winner}
+	for _, p := range h.players {
+		msg.Players = append(msg.Players, *p)
+	}
+
+	for conn, enc := range h.conns {
+		if err := enc.Encode(msg); err != nil {
+			conn.Close()
+		}
+	}
+}
+
+// uniqueName must be called with the lock held.
+func (h *Host) uniqueName(name string) string {
+	if name == "" {
+		name = "player"
+	}
+
+	unique := name
+	for i := 2; ; i++ {
+		taken := false
+		for _, p := range h.players {
+			taken = taken || p.Name == unique
+		}
+		if !taken {
+			return unique
+		}
+		unique = fmt.Sprintf("%s %d", name, i)
+	}
+}
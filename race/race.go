@@ -0,0 +1,79 @@
+// Package race implements a head-to-head mode where players on the same
+// network race to solve the same word. One instance hosts the race and
+// every player, including the host, joins it as a client.
+//
+// The protocol is newline delimited JSON over TCP. Clients send a join
+// message and get back a start message with the word. After every guess
+// they send their progress, only the colors of each row, never the
+// letters. The host broadcasts the state of the race to every player on
+// each change and the first player to solve the word wins.
+package race
+
+import (
+	"github.com/Alvaroalonsobabbel/wordle/wordle"
+)
+
+const (
+	msgJoin     = "join"
+	msgStart    = "start"
+	msgProgress = "progress"
+	msgState    = "state"
+)
+
+type message struct {
+	Type    string   `json:"type"`
+	Player  string   `json:"player,omitempty"`
+	Word    string   `json:"word,omitempty"`
+	Rows    [][]int  `json:"rows,omitempty"`
+	Solved  bool     `json:"solved,omitempty"`
+	Players []Player `json:"players,omitempty"`
+	Winner  string   `json:"winner,omitempty"`
+}
+
+// Player is the progress of a player in the race. Rows hold
+// the wordle.Correct, wordle.Present and wordle.Absent results
+// of each guess without the letters.
+type Player struct {
+	Name   string  `json:"name"`
+	Rows   [][]int `json:"rows"`
+	Solved bool    `json:"solved"`
+}
+
+// Update is the state of the race as seen by a player.
+type Update struct {
+	Opponents []Player
+	Winner    string
+}
+
+func rows(results [][]map[rune]int) [][]int {
+	var rows [][]int
+	for _, res := range results {
+		var row []int
+		for _, stat := range res {
+			for _, v := range stat {
+				row = append(row, v)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+func solved(s *wordle.Status) bool {
+	return s.Wordle != "" && string(s.Discovered[:]) == s.Wordle
+}
+
+// solvedRows reports whether the last of rows is all wordle.Correct.
+func solvedRows(rows [][]int) bool {
+	if len(rows) == 0 || len(rows[len(rows)-1]) == 0 {
+		return false
+	}
+	for _, v := range rows[len(rows)-1] {
+		if v != wordle.Correct {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,182 @@
+package race
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Alvaroalonsobabbel/wordle/wordle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestHost(t *testing.T, word string) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	h := NewHost(l, word)
+	h.Start()
+	go h.Serve() //nolint: errcheck
+	t.Cleanup(func() { h.Close() })
+
+	return l.Addr().String()
+}
+
+func join(t *testing.T, addr, name string) *Client {
+	t.Helper()
+	c, err := Join(addr, name)
+	require.NoError(t, err)
+	t.Cleanup(func() { c.Close() })
+
+	return c
+}
+
+// waitFor returns the first update matching fn.
+func waitFor(t *testing.T, c *Client, fn func(Update) bool) Update {
+	t.Helper()
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case u, ok := <-c.Updates():
+			require.True(t, ok, "connection closed")
+			if fn(u) {
+				return u
+			}
+		case <-timeout:
+			require.FailNow(t, "timed out waiting for update")
+		}
+	}
+}
+
+func TestRace(t *testing.T) {
+	addr := newTestHost(t, "HELLO")
+	alice := join(t, addr, "alice")
+	bob := join(t, addr, "bob")
+
+	t.Run("players get the same word", func(t *testing.T) {
+		assert.Equal(t, "HELLO", alice.Word)
+		assert.Equal(t, "HELLO", bob.Word)
+	})
+
+	t.Run("players see their opponents join", func(t *testing.T) {
+		u := waitFor(t, alice, func(u Update) bool { return len(u.Opponents) == 1 })
+		assert.Equal(t, "bob", u.Opponents[0].Name)
+	})
+
+	aliceGame := &wordle.Status{Wordle: alice.Word}
+	bobGame := &wordle.Status{Wordle: bob.Word}
+
+	t.Run("players see their opponents progress without letters", func(t *testing.T) {
+		assert.NoError(t, aliceGame.Try("CELLO"))
+		assert.NoError(t, alice.Report(aliceGame))
+
+		u := waitFor(t, bob, func(u Update) bool { return len(u.Opponents) == 1 && len(u.Opponents[0].Rows) == 1 })
+		assert.Equal(t, Player{
+			Name: "alice",
+			Rows: [][]int{{wordle.Absent, wordle.Correct, wordle.Correct, wordle.Correct, wordle.Correct}},
+		}, u.Opponents[0])
+		assert.Empty(t, u.Winner)
+	})
+
+	t.Run("the first player to solve the word wins", func(t *testing.T) {
+		assert.NoError(t, bobGame.Try("HELLO"))
+		assert.NoError(t, bob.Report(bobGame))
+		u := waitFor(t, alice, func(u Update) bool { return u.Winner != "" })
+		assert.Equal(t, "bob", u.Winner)
+		assert.True(t, u.Opponents[0].Solved)
+
+		assert.NoError(t, aliceGame.Try("HELLO"))
+		assert.NoError(t, alice.Report(aliceGame))
+		u = waitFor(t, bob, func(u Update) bool { return len(u.Opponents[0].Rows) == 2 })
+		assert.Equal(t, "bob", u.Winner)
+	})
+}
+
+func TestProgress(t *testing.T) {
+	addr := newTestHost(t, "HELLO")
+	alice := join(t, addr, "alice")
+	bob := join(t, addr, "bob")
+	waitFor(t, alice, func(u Update) bool { return len(u.Opponents) == 1 })
+
+	t.Run("a solved claim the rows don't show is ignored", func(t *testing.T) {
+		row := []int{wordle.Absent, wordle.Correct, wordle.Correct, wordle.Correct, wordle.Correct}
+		require.NoError(t, bob.enc.Encode(message{Type: msgProgress, Rows: [][]int{row}, Solved: true}))
+		require.NoError(t, bob.enc.Encode(message{Type: msgProgress, Rows: [][]int{row, row}}))
+
+		u := waitFor(t, alice, func(u Update) bool { return len(u.Opponents[0].Rows) > 0 })
+		assert.Len(t, u.Opponents[0].Rows, 2)
+		assert.False(t, u.Opponents[0].Solved)
+		assert.Empty(t, u.Winner)
+	})
+}
+
+func TestJoin(t *testing.T) {
+	t.Run("players wait for the host to start the race", func(t *testing.T) {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		h := NewHost(l, "HELLO")
+		go h.Serve() //nolint: errcheck
+		defer h.Close()
+
+		joined := make(chan *Client)
+		go func() {
+			c, err := Join(l.Addr().String(), "alice")
+			assert.NoError(t, err)
+			joined <- c
+		}()
+
+		select {
+		case <-joined:
+			require.FailNow(t, "joined before the race started")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		h.Start()
+		c := <-joined
+		defer c.Close()
+		assert.Equal(t, "HELLO", c.Word)
+	})
+
+	t.Run("duplicated names are made unique", func(t *testing.T) {
+		addr := newTestHost(t, "HELLO")
+		first := join(t, addr, "alice")
+		second := join(t, addr, "alice")
+		third := join(t, addr, "")
+
+		assert.Equal(t, "alice", first.Name())
+		assert.Equal(t, "alice 2", second.Name())
+		assert.Equal(t, "player", third.Name())
+	})
+
+	t.Run("joining a race that doesn't exist returns an error", func(t *testing.T) {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		l.Close()
+
+		_, err = Join(l.Addr().String(), "alice")
+		assert.Error(t, err)
+	})
+
+	t.Run("updates are closed when the host goes away", func(t *testing.T) {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		h := NewHost(l, "HELLO")
+		h.Start()
+		go h.Serve() //nolint: errcheck
+		c := join(t, l.Addr().String(), "alice")
+
+		assert.NoError(t, h.Close())
+		timeout := time.After(time.Second)
+		for {
+			select {
+			case _, ok := <-c.Updates():
+				if !ok {
+					return
+				}
+			case <-timeout:
+				require.FailNow(t, "updates not closed")
+			}
+		}
+	})
+}
@@ -0,0 +1,68 @@
+package sshserver
+
+import (
+	"sync"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// pty is the terminal.TTY of an SSH session. The client's terminal is
+// already in raw mode so there's nothing to do other than tracking its size.
+type pty struct {
+	mu            sync.Mutex
+	term          string
+	width, height int
+	resized       chan struct{}
+}
+
+// newPty reads the window size from a pty-req payload, see RFC 4254 6.2.
+func newPty(payload []byte) *pty {
+	var req struct {
+		Term          string
+		Width, Height uint32
+		Rest          []byte `ssh:"rest"`
+	}
+	p := &pty{resized: make(chan struct{}, 1)}
+	if err := ssh.Unmarshal(payload, &req); err != nil {
+		return p
+	}
+	p.term, p.width, p.height = req.Term, int(req.Width), int(req.Height)
+
+	return p
+}
+
+// windowChange reads the new window size from a window-change payload, see RFC 4254 6.7.
+func (p *pty) windowChange(payload []byte) bool {
+	var req struct {
+		Width, Height, PixelWidth, PixelHeight uint32
+	}
+	if err := ssh.Unmarshal(payload, &req); err != nil {
+		return false
+	}
+
+	p.mu.Lock()
+	p.width, p.height = int(req.Width), int(req.Height)
+	p.mu.Unlock()
+
+	select {
+	case p.resized <- struct{}{}:
+	default:
+	}
+
+	return true
+}
+
+func (p *pty) MakeRaw() (func() error, error) {
+	return func() error { return nil }, nil
+}
+
+func (p *pty) Size() (int, int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.width, p.height, nil
+}
+
+func (p *pty) Resized() <-chan struct{} {
+	return p.resized
+}
@@ -0,0 +1,232 @@
+// Package sshserver hosts the game over SSH so it can be played without
+// installing anything. Every SSH session plays its own game and each
+// player, identified by their public key, has their own saved status.
+package sshserver
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/Alvaroalonsobabbel/wordle/status"
+	"github.com/Alvaroalonsobabbel/wordle/terminal"
+	"github.com/Alvaroalonsobabbel/wordle/theme"
+	"github.com/Alvaroalonsobabbel/wordle/wordle"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	fingerprintExt = "fingerprint"
+	usersDir       = "users"
+)
+
+// GameFunc returns the game to play given the player's saved status, nil
+// when the player has none.
+type GameFunc func(saved *wordle.Status) (*wordle.Status, error)
+
+type Server struct {
+	config *ssh.ServerConfig
+	dir    string
+	game   GameFunc
+}
+
+// NewServer returns an SSH server that stores each player's status in dir.
+func NewServer(hostKey ssh.Signer, dir string, game GameFunc) *Server {
+	config := &ssh.ServerConfig{
+		// Any key is welcome, it's only used to tell players apart.
+		PublicKeyCallback: func(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			return &ssh.Permissions{Extensions: map[string]string{fingerprintExt: fingerprint(key)}}, nil
+		},
+	}
+	config.AddHostKey(hostKey)
+
+	return &Server{config: config, dir: dir, game: game}
+}
+
+// Serve accepts SSH connections until the listener is closed.
+func (s *Server) Serve(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+
+		go s.handle(conn)
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	sconn, chans, reqs, err := ssh.NewServerConn(conn, s.config)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	defer sconn.Close()
+	go ssh.DiscardRequests(reqs)
+
+	for ch := range chans {
+		if ch.ChannelType() != "session" {
+			ch.Reject(ssh.UnknownChannelType, "only sessions are supported") //nolint: errcheck
+			continue
+		}
+
+		channel, requests, err := ch.Accept()
+		if err != nil {
+			continue
+		}
+		go s.session(channel, requests, sconn.Permissions.Extensions[fingerprintExt])
+	}
+}
+
+// session plays a game once the client asks for a shell on a pty.
+func (s *Server) session(channel ssh.Channel, requests <-chan *ssh.Request, player string) {
+	defer channel.Close()
+
+	var (
+		tty *pty
+		env = map[string]string{}
+	)
+	for req := range requests {
+		switch req.Type {
+		case "env":
+			var v struct{ Name, Value string }
+			if err := ssh.Unmarshal(req.Payload, &v); err == nil {
+				env[v.Name] = v.Value
+			}
+			if req.WantReply {
+				req.Reply(true, nil) //nolint: errcheck
+			}
+		case "pty-req":
+			tty = newPty(req.Payload)
+			req.Reply(true, nil) //nolint: errcheck
+		case "shell":
+			req.Reply(true, nil) //nolint: errcheck
+			go windowChanges(requests, tty)
+
+			var code uint32 = 1
+			if tty == nil {
+				fmt.Fprint(channel.Stderr(), "A terminal is required to play, use 'ssh -t'.\r\n")
+			} else {
+				env["TERM"] = tty.term
+				code = s.play(channel, player, tty, theme.Detect(func(k string) string { return env[k] }))
+			}
+			channel.SendRequest("exit-status", false, ssh.Marshal(struct{ Code uint32 }{code})) //nolint: errcheck
+			return
+		default:
+			if req.WantReply {
+				req.Reply(false, nil) //nolint: errcheck
+			}
+		}
+	}
+}
+
+// windowChanges keeps the pty size up to date while the game is played,
+// any other request is discarded.
+func windowChanges(requests <-chan *ssh.Request, tty *pty) {
+	for req := range requests {
+		ok := req.Type == "window-change" && tty != nil && tty.windowChange(req.Payload)
+		if req.WantReply {
+			req.Reply(ok, nil) //nolint: errcheck
+		}
+	}
+}
+
+func (s *Server) play(channel ssh.Channel, player string, tty *pty, colors theme.Profile) uint32 {
+	dir := filepath.Join(s.dir, usersDir, player)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		log.Printf("error creating status directory for %s: %v", player, err)
+		fmt.Fprint(channel, "Unable to load your game.\r\n")
+		return 1
+	}
+	if err := status.Migrate(dir, dir); err != nil {
+		log.Printf("error migrating status for %s: %v", player, err)
+	}
+	store := status.InDir(dir)
+
+	// Only one session of a player saves their game.
+	saver := terminal.WithSaver(store)
+	l, err := status.Lock(dir)
+	switch {
+	case errors.Is(err, status.ErrLocked):
+		fmt.Fprint(channel, "Your game is open in another session, this one won't be saved.\r\n")
+		saver = terminal.WithSaver(nil)
+	case err != nil:
+		log.Printf("error locking status for %s: %v", player, err)
+	default:
+		defer l.Unlock() //nolint: errcheck
+	}
+
+	saved, err := store.LoadGame()
+	if err != nil {
+		log.Printf("error loading status for %s: %v", player, err)
+	}
+	game, err := s.game(saved)
+	if err != nil {
+		fmt.Fprintf(channel, "%v\r\n", err)
+		return 1
+	}
+
+	err = terminal.New(game,
+		terminal.WithInput(channel),
+		terminal.WithOutput(channel),
+		terminal.WithTTY(tty),
+		saver,
+		terminal.WithOSC52Clipboard(),
+		// Images would be saved in the server, not where the player is.
+		terminal.WithImageWriter(nil),
+		terminal.WithColors(colors),
+		// Shutting the server down is up to whoever runs it.
+		terminal.WithSignals(nil),
+	).Start()
+	if err != nil {
+		log.Printf("error playing for %s: %v", player, err)
+		return 1
+	}
+
+	return 0
+}
+
+// fingerprint identifies a public key with a string safe to be used as a directory name.
+func fingerprint(key ssh.PublicKey) string {
+	sum := sha256.Sum256(key.Marshal())
+	return hex.EncodeToString(sum[:])
+}
+
+// HostKey loads the server's private key from path, creating one if it doesn't exist.
+func HostKey(path string) (ssh.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err == nil {
+		return ssh.ParsePrivateKey(data)
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("error reading host key: %v", err)
+	}
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("error generating host key: %v", err)
+	}
+	block, err := ssh.MarshalPrivateKey(key, "wordle")
+	if err != nil {
+		return nil, fmt.Errorf("error encoding host key: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("error creating host key directory: %v", err)
+	}
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), 0600); err != nil {
+		return nil, fmt.Errorf("error writing host key: %v", err)
+	}
+
+	return ssh.NewSignerFromKey(key)
+}
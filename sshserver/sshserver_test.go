@@ -0,0 +1,203 @@
+package sshserver
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Alvaroalonsobabbel/wordle/status"
+	"github.com/Alvaroalonsobabbel/wordle/wordle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func newTestServer(t *testing.T) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	hostKey, err := HostKey(filepath.Join(dir, "host_key"))
+	require.NoError(t, err)
+
+	s := NewServer(hostKey, dir, func(saved *wordle.Status) (*wordle.Status, error) {
+		game := &wordle.Status{Wordle: "HELLO", PuzzleNumber: 1}
+		wordle.WithSavedWordle(saved)(game)
+		return game, nil
+	})
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go s.Serve(l) //nolint: errcheck
+	t.Cleanup(func() { l.Close() })
+
+	return l.Addr().String(), dir
+}
+
+func newClientKey(t *testing.T) ssh.Signer {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(key)
+	require.NoError(t, err)
+
+	return signer
+}
+
+// play opens a session, types the input and waits for the session to end.
+func play(t *testing.T, addr string, key ssh.Signer, pty bool, input string) string {
+	t.Helper()
+	client, err := ssh.Dial("tcp", addr, &ssh.ClientConfig{
+		User:            "player",
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(key)},
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(), //nolint: gosec
+	})
+	require.NoError(t, err)
+	defer client.Close()
+
+	session, err := client.NewSession()
+	require.NoError(t, err)
+	defer session.Close()
+
+	if pty {
+		require.NoError(t, session.RequestPty("xterm", 24, 80, ssh.TerminalModes{}))
+	}
+	out := &syncBuffer{}
+	session.Stdout = out
+	session.Stderr = out
+	stdin, err := session.StdinPipe()
+	require.NoError(t, err)
+
+	if err := session.Shell(); err != nil {
+		return out.String()
+	}
+	_, err = io.WriteString(stdin, input)
+	require.NoError(t, err)
+
+	done := make(chan error)
+	go func() { done <- session.Wait() }()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "session didn't end")
+	}
+
+	return out.String()
+}
+
+func TestServer(t *testing.T) {
+	addr, dir := newTestServer(t)
+	alice, bob := newClientKey(t), newClientKey(t)
+
+	t.Run("plays the game", func(t *testing.T) {
+		out := play(t, addr, alice, true, "CHAIR\r\x03")
+		assert.Contains(t, out, "6 attempts to find a 5-letter word")
+		assert.Contains(t, out, " C ")
+	})
+
+	t.Run("each player has their own status", func(t *testing.T) {
+		saved, err := status.InDir(filepath.Join(dir, usersDir, fingerprint(alice.PublicKey()))).LoadGame()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, saved.Round)
+
+		entries, err := os.ReadDir(filepath.Join(dir, usersDir))
+		assert.NoError(t, err)
+		assert.Len(t, entries, 1)
+
+		play(t, addr, bob, true, "\x03")
+		entries, err = os.ReadDir(filepath.Join(dir, usersDir))
+		assert.NoError(t, err)
+		assert.Len(t, entries, 2)
+	})
+
+	t.Run("a player continues their saved game", func(t *testing.T) {
+		play(t, addr, alice, true, "HELLO\r\x03")
+		saved, err := status.InDir(filepath.Join(dir, usersDir, fingerprint(alice.PublicKey()))).LoadGame()
+		assert.NoError(t, err)
+		assert.Equal(t, 2, saved.Round)
+		assert.True(t, saved.Finish())
+	})
+
+	t.Run("a game open in another session is not saved", func(t *testing.T) {
+		bobDir := filepath.Join(dir, usersDir, fingerprint(bob.PublicKey()))
+		l, err := status.Lock(bobDir)
+		require.NoError(t, err)
+		defer l.Unlock() //nolint: errcheck
+
+		out := play(t, addr, bob, true, "CHAIR\r\x03")
+		assert.Contains(t, out, "Your game is open in another session")
+		saved, err := status.InDir(bobDir).LoadGame()
+		assert.NoError(t, err)
+		assert.Equal(t, 0, saved.Round)
+	})
+
+	t.Run("a pty is required", func(t *testing.T) {
+		out := play(t, addr, alice, false, "")
+		assert.Contains(t, out, "A terminal is required")
+	})
+}
+
+func TestHostKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys", "host_key")
+	key, err := HostKey(path)
+	assert.NoError(t, err)
+	assert.FileExists(t, path)
+
+	again, err := HostKey(path)
+	assert.NoError(t, err)
+	assert.Equal(t, key.PublicKey().Marshal(), again.PublicKey().Marshal())
+}
+
+func TestNewPty(t *testing.T) {
+	payload := ssh.Marshal(struct {
+		Term                                   string
+		Width, Height, PixelWidth, PixelHeight uint32
+		Modes                                  string
+	}{"xterm", 100, 30, 0, 0, ""})
+
+	w, h, err := newPty(payload).Size()
+	assert.NoError(t, err)
+	assert.Equal(t, 100, w)
+	assert.Equal(t, 30, h)
+}
+
+func TestWindowChange(t *testing.T) {
+	tty := newPty(nil)
+	assert.True(t, tty.windowChange(ssh.Marshal(struct {
+		Width, Height, PixelWidth, PixelHeight uint32
+	}{120, 40, 0, 0})))
+
+	select {
+	case <-tty.Resized():
+	default:
+		t.Fatal("resize not notified")
+	}
+	w, h, err := tty.Size()
+	assert.NoError(t, err)
+	assert.Equal(t, 120, w)
+	assert.Equal(t, 40, h)
+
+	assert.False(t, tty.windowChange([]byte("invalid")))
+}
@@ -0,0 +1,105 @@
+package status
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// atomicFile is written to a temporary file which replaces the file once
+// it's closed, so a crash never leaves it half written. Nothing is
+// replaced if a write fails.
+type atomicFile struct {
+	file   *os.File
+	path   string
+	failed bool
+	closed bool
+}
+
+// WriteFile replaces the file at path with data in a single step, like
+// the files of the status.
+func WriteFile(path string, data []byte) error {
+	f, err := createAtomic(path)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close() //nolint: errcheck
+		return fmt.Errorf("error writing %s file: %v", filepath.Base(path), err)
+	}
+
+	return f.Close()
+}
+
+func createAtomic(path string) (*atomicFile, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return nil, fmt.Errorf("error creating %s file: %v", filepath.Base(path), err)
+	}
+	f := &atomicFile{file: tmp, path: path}
+	if err := tmp.Chmod(0600); err != nil {
+		f.discard()
+		return nil, fmt.Errorf("error creating %s file: %v", filepath.Base(path), err)
+	}
+
+	return f, nil
+}
+
+func (f *atomicFile) Read(p []byte) (int, error) {
+	return f.file.Read(p)
+}
+
+func (f *atomicFile) Write(p []byte) (int, error) {
+	n, err := f.file.Write(p)
+	if err != nil {
+		f.failed = true
+	}
+
+	return n, err
+}
+
+// Close replaces the file with what was written, closing it more than
+// once does nothing.
+func (f *atomicFile) Close() error {
+	if f.closed {
+		return nil
+	}
+	f.closed = true
+
+	if f.failed {
+		f.discard()
+		return nil
+	}
+	if err := f.file.Sync(); err != nil {
+		f.discard()
+		return fmt.Errorf("error writing %s file: %v", filepath.Base(f.path), err)
+	}
+	if err := f.file.Close(); err != nil {
+		f.discard()
+		return fmt.Errorf("error writing %s file: %v", filepath.Base(f.path), err)
+	}
+	if err := os.Rename(f.file.Name(), f.path); err != nil {
+		f.discard()
+		return fmt.Errorf("error replacing %s file: %v", filepath.Base(f.path), err)
+	}
+	syncDir(filepath.Dir(f.path))
+
+	return nil
+}
+
+// discard removes the temporary file, leaving the file as it was.
+func (f *atomicFile) discard() {
+	f.file.Close()           //nolint: errcheck
+	os.Remove(f.file.Name()) //nolint: errcheck
+}
+
+// syncDir persists the rename, where directories can be synced.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	defer d.Close()
+
+	d.Sync() //nolint: errcheck
+}
@@ -0,0 +1,81 @@
+package status
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAtomicFile(t *testing.T) {
+	write := func(t *testing.T, path, data string) {
+		t.Helper()
+		f, err := createAtomic(path)
+		assert.NoError(t, err)
+		_, err = f.Write([]byte(data))
+		assert.NoError(t, err)
+		assert.NoError(t, f.Close())
+		assert.NoError(t, f.Close(), "closing twice does nothing")
+	}
+
+	t.Run("replaces the file once closed", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), statusFile)
+		write(t, path, "first")
+		write(t, path, "second")
+
+		got, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, "second", string(got))
+		entries, err := os.ReadDir(filepath.Dir(path))
+		assert.NoError(t, err)
+		assert.Len(t, entries, 1, "no temporary files are left")
+	})
+
+	t.Run("the file is untouched until closed", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), statusFile)
+		write(t, path, "first")
+
+		f, err := createAtomic(path)
+		assert.NoError(t, err)
+		_, err = f.Write([]byte(`{"round":`))
+		assert.NoError(t, err)
+		got, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, "first", string(got))
+		assert.NoError(t, f.Close())
+	})
+
+	t.Run("a failed write keeps the file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), statusFile)
+		write(t, path, "first")
+
+		f, err := createAtomic(path)
+		assert.NoError(t, err)
+		assert.NoError(t, f.file.Close())
+		_, err = f.Write([]byte("second"))
+		assert.True(t, errors.Is(err, os.ErrClosed))
+		assert.NoError(t, f.Close())
+
+		got, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, "first", string(got))
+		entries, err := os.ReadDir(filepath.Dir(path))
+		assert.NoError(t, err)
+		assert.Len(t, entries, 1)
+	})
+}
+
+func TestWriteFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.json")
+	assert.NoError(t, WriteFile(path, []byte("[1]")))
+	assert.NoError(t, WriteFile(path, []byte("[1,2]")))
+
+	got, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "[1,2]", string(got))
+	tmp, err := filepath.Glob(path + ".*.tmp")
+	assert.NoError(t, err)
+	assert.Empty(t, tmp)
+}
@@ -0,0 +1,194 @@
+package status
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Alvaroalonsobabbel/wordle/wordle"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown format")
+	ErrInvalidRecord = errors.New("invalid record")
+
+	csvHeader = []string{"puzzle_number", "wordle", "won", "guesses", "hard_mode", "finished"}
+)
+
+// Export writes the records to w in format, csv or json.
+func Export(w io.Writer, records []Record, format string) error {
+	switch format {
+	case FormatJSON:
+		if records == nil {
+			records = []Record{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(records); err != nil {
+			return fmt.Errorf("error exporting history: %v", err)
+		}
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		cw.Write(csvHeader) //nolint: errcheck
+		for _, r := range records {
+			cw.Write([]string{ //nolint: errcheck
+				strconv.Itoa(r.PuzzleNumber),
+				r.Wordle,
+				strconv.FormatBool(r.Won),
+				strconv.Itoa(r.Guesses),
+				strconv.FormatBool(r.HardMode),
+				r.Finished.Format(time.RFC3339),
+			})
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return fmt.Errorf("error exporting history: %v", err)
+		}
+	default:
+		return fmt.Errorf("%w %q, choose one of csv or json", ErrUnknownFormat, format)
+	}
+
+	return nil
+}
+
+// Import reads the records exported in any format, or the results
+// pasted from Share as played here or in the NYT game.
+func Import(r io.Reader) ([]Record, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("error reading history: %v", err)
+	}
+
+	// Spreadsheets may start the files they save with a byte order mark.
+	text := strings.TrimSpace(string(bytes.TrimPrefix(data, []byte("\ufeff"))))
+	switch {
+	case strings.HasPrefix(text, "["):
+		return importJSON(text)
+	case strings.HasPrefix(text, strings.Join(csvHeader, ",")):
+		return importCSV(text)
+	default:
+		return importShares(text)
+	}
+}
+
+func importJSON(text string) ([]Record, error) {
+	var records []Record
+	if err := json.Unmarshal([]byte(text), &records); err != nil {
+		return nil, fmt.Errorf("error decoding history: %v", err)
+	}
+	for i, r := range records {
+		if err := r.validate(); err != nil {
+			return nil, fmt.Errorf("record %d: %w", i+1, err)
+		}
+	}
+
+	return records, nil
+}
+
+func importCSV(text string) ([]Record, error) {
+	cr := csv.NewReader(strings.NewReader(text))
+	cr.FieldsPerRecord = len(csvHeader)
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("error decoding history: %v", err)
+	}
+
+	records := make([]Record, 0, len(rows)-1)
+	for i, row := range rows[1:] {
+		r, err := csvRecord(row)
+		if err == nil {
+			err = r.validate()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+2, err)
+		}
+		records = append(records, r)
+	}
+
+	return records, nil
+}
+
+func csvRecord(row []string) (Record, error) {
+	var (
+		r    = Record{Wordle: row[1]}
+		errs [5]error
+	)
+	r.PuzzleNumber, errs[0] = strconv.Atoi(row[0])
+	r.Won, errs[1] = strconv.ParseBool(row[2])
+	r.Guesses, errs[2] = strconv.Atoi(row[3])
+	r.HardMode, errs[3] = strconv.ParseBool(row[4])
+	r.Finished, errs[4] = time.Parse(time.RFC3339, row[5])
+	if err := errors.Join(errs[:]...); err != nil {
+		return r, fmt.Errorf("%w: %v", ErrInvalidRecord, err)
+	}
+
+	return r, nil
+}
+
+// importShares reads every result pasted, each of them starting with its
+// "Wordle 1234 3/6" header. Whatever was pasted along with them, such as
+// chat messages, is ignored.
+func importShares(text string) ([]Record, error) {
+	var blocks []string
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case wordle.IsShareHeader(line):
+			blocks = append(blocks, line)
+		case len(blocks) > 0:
+			blocks[len(blocks)-1] += "\n" + line
+		}
+	}
+	if len(blocks) == 0 {
+		return nil, fmt.Errorf("%w: no results found", wordle.ErrInvalidShare)
+	}
+
+	records := make([]Record, 0, len(blocks))
+	for i, b := range blocks {
+		shared, err := wordle.ParseShare(b)
+		if err != nil {
+			return nil, fmt.Errorf("result %d: %w", i+1, err)
+		}
+		records = append(records, sharedRecord(shared))
+	}
+
+	return records, nil
+}
+
+// sharedRecord is the record of a shared result, which has no answer. It's
+// taken as finished the day the puzzle was published.
+func sharedRecord(s wordle.Shared) Record {
+	return Record{
+		PuzzleNumber: s.PuzzleNumber,
+		Won:          s.Won(),
+		Guesses:      len(s.Rows),
+		HardMode:     s.HardMode,
+		Finished:     wordle.PuzzleDate(s.PuzzleNumber),
+	}
+}
+
+func (r Record) validate() error {
+	switch {
+	case r.PuzzleNumber < 0:
+		return fmt.Errorf("%w: puzzle number %d", ErrInvalidRecord, r.PuzzleNumber)
+	case r.Guesses < 1 || r.Guesses > 6:
+		return fmt.Errorf("%w: %d guesses", ErrInvalidRecord, r.Guesses)
+	case !r.Won && r.Guesses != 6:
+		return fmt.Errorf("%w: lost in %d guesses", ErrInvalidRecord, r.Guesses)
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package status
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Alvaroalonsobabbel/wordle/wordle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExport(t *testing.T) {
+	finished := time.Date(2024, time.March, 1, 10, 30, 0, 0, time.UTC)
+	records := []Record{
+		{PuzzleNumber: 985, Wordle: "CHAIR", Won: true, Guesses: 3, HardMode: true, Finished: finished},
+		{PuzzleNumber: 986, Wordle: "PIANO", Guesses: 6, Finished: finished.AddDate(0, 0, 1)},
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, Export(&buf, records, FormatCSV))
+		assert.Equal(t, "puzzle_number,wordle,won,guesses,hard_mode,finished\n"+
+			"985,CHAIR,true,3,true,2024-03-01T10:30:00Z\n"+
+			"986,PIANO,false,6,false,2024-03-02T10:30:00Z\n", buf.String())
+
+		got, err := Import(&buf)
+		require.NoError(t, err)
+		assert.Equal(t, records, got)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, Export(&buf, records, FormatJSON))
+		got, err := Import(&buf)
+		require.NoError(t, err)
+		assert.Equal(t, records, got)
+	})
+
+	t.Run("an empty history", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, Export(&buf, nil, FormatJSON))
+		assert.Equal(t, "[]\n", buf.String())
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		err := Export(&bytes.Buffer{}, records, "xml")
+		assert.ErrorIs(t, err, ErrUnknownFormat)
+	})
+}
+
+func TestImport(t *testing.T) {
+	t.Run("pasted results", func(t *testing.T) {
+		paste := "Look at this one!\n" +
+			"Wordle 1,234 3/6*\n\n⬛🟨⬛⬛⬛\n⬛🟩🟩⬛🟩\n🟩🟩🟩🟩🟩\n" +
+			"Not so lucky today\n" +
+			"Wordle 1,235 X/6\n" + strings.Repeat("⬜️⬜️🟨⬜️⬜️\n", 6)
+
+		got, err := Import(strings.NewReader(paste))
+		require.NoError(t, err)
+		assert.Equal(t, []Record{
+			{PuzzleNumber: 1234, Won: true, Guesses: 3, HardMode: true, Finished: time.Date(2024, time.November, 4, 0, 0, 0, 0, time.UTC)},
+			{PuzzleNumber: 1235, Guesses: 6, Finished: time.Date(2024, time.November, 5, 0, 0, 0, 0, time.UTC)},
+		}, got)
+	})
+
+	t.Run("pasted results with the blank space of chat apps", func(t *testing.T) {
+		paste := "Wordle\u00a01,234\u00a02/6\n⬜🟩🟩🟩🟩\n🟩🟩🟩🟩🟩\n" +
+			"  Wordle\t1,235 1/6*\n🟩🟩🟩🟩🟩\n"
+
+		got, err := Import(strings.NewReader(paste))
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+		assert.Equal(t, 1234, got[0].PuzzleNumber)
+		assert.Equal(t, 2, got[0].Guesses)
+		assert.True(t, got[1].HardMode)
+	})
+
+	t.Run("a result shared by the game", func(t *testing.T) {
+		game := &wordle.Status{Wordle: "HELLO", PuzzleNumber: 1500}
+		assert.NoError(t, game.Try("CELLO"))
+		assert.NoError(t, game.Try("HELLO"))
+
+		got, err := Import(strings.NewReader(game.Share()))
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		assert.Equal(t, 2, got[0].Guesses)
+	})
+
+	tests := []struct {
+		name, input, wantErr string
+	}{
+		{"nothing", "", "invalid shared result: no results found"},
+		{"an invalid result", "Wordle 1 2/6\n🟩🟩🟩🟩🟩", "result 1: invalid shared result: score doesn't match the grid"},
+		{"an invalid csv line", "puzzle_number,wordle,won,guesses,hard_mode,finished\n1,CHAIR,yes,3,false,2024-03-01T10:30:00Z", "line 2: invalid record: strconv.ParseBool: parsing \"yes\": invalid syntax"},
+		{"too many guesses", "puzzle_number,wordle,won,guesses,hard_mode,finished\n1,CHAIR,true,7,false,2024-03-01T10:30:00Z", "line 2: invalid record: 7 guesses"},
+		{"a lost game with few guesses", `[{"puzzle_number":1,"won":false,"guesses":3}]`, "record 1: invalid record: lost in 3 guesses"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Import(strings.NewReader(test.input))
+			assert.EqualError(t, err, test.wantErr)
+		})
+	}
+}
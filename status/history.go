@@ -0,0 +1,137 @@
+package status
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+	"time"
+
+	"github.com/Alvaroalonsobabbel/wordle/wordle"
+)
+
+const historyFile = "history.json"
+
+// Record is a finished game.
+type Record struct {
+	PuzzleNumber int       `json:"puzzle_number"`
+	Wordle       string    `json:"wordle"`
+	Won          bool      `json:"won"`
+	Guesses      int       `json:"guesses"`
+	HardMode     bool      `json:"hard_mode"`
+	Finished     time.Time `json:"finished"`
+}
+
+// Stats sums up the history of games.
+type Stats struct {
+	Played int `json:"played"`
+	Won    int `json:"won"`
+	// CurrentStreak is how many consecutive puzzles were won up to the
+	// last one played, MaxStreak the longest of those runs.
+	CurrentStreak int `json:"current_streak"`
+	MaxStreak     int `json:"max_streak"`
+	// Guesses is how many games were won in 1 to 6 guesses.
+	Guesses [6]int `json:"guesses"`
+}
+
+func newRecord(w *wordle.Status) Record {
+	return Record{
+		PuzzleNumber: w.PuzzleNumber,
+		Wordle:       w.Wordle,
+		Won:          string(w.Discovered[:]) == w.Wordle,
+		Guesses:      w.Round,
+		HardMode:     w.HardMode,
+		Finished:     time.Now().UTC(),
+	}
+}
+
+func (s *status) History() ([]Record, error) {
+	file, err := s.store.Open(historyFile)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var records []Record
+	if err := json.NewDecoder(file).Decode(&records); err != nil {
+		return nil, fmt.Errorf("error decoding history file: %v", err)
+	}
+
+	return records, nil
+}
+
+func (s *status) Stats() (Stats, error) {
+	records, err := s.History()
+	if err != nil {
+		return Stats{}, err
+	}
+
+	return stats(records), nil
+}
+
+func (s *status) AddHistory(add ...Record) error {
+	records, err := s.History()
+	if err != nil {
+		return err
+	}
+
+	merged := mergeRecords(records, add)
+	if len(merged) == len(records) {
+		return nil
+	}
+
+	file, err := s.store.Create(historyFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := json.NewEncoder(file).Encode(merged); err != nil {
+		return fmt.Errorf("error encoding history file: %v", err)
+	}
+
+	return file.Close()
+}
+
+// mergeRecords adds to records the ones in add of puzzles not played yet.
+func mergeRecords(records, add []Record) []Record {
+	merged := slices.Clone(records)
+	for _, r := range add {
+		if !slices.ContainsFunc(merged, func(m Record) bool { return m.PuzzleNumber == r.PuzzleNumber }) {
+			merged = append(merged, r)
+		}
+	}
+	slices.SortFunc(merged, func(a, b Record) int { return a.PuzzleNumber - b.PuzzleNumber })
+
+	return merged
+}
+
+func stats(records []Record) Stats {
+	var st Stats
+	last := 0
+	for _, r := range records {
+		st.Played++
+		if !r.Won {
+			st.CurrentStreak = 0
+			last = r.PuzzleNumber
+			continue
+		}
+
+		st.Won++
+		if r.Guesses >= 1 && r.Guesses <= len(st.Guesses) {
+			st.Guesses[r.Guesses-1]++
+		}
+		if r.PuzzleNumber != last+1 {
+			st.CurrentStreak = 0
+		}
+		st.CurrentStreak++
+		st.MaxStreak = max(st.MaxStreak, st.CurrentStreak)
+		last = r.PuzzleNumber
+	}
+
+	return st
+}
@@ -0,0 +1,59 @@
+package status
+
+import (
+	"testing"
+
+	"github.com/Alvaroalonsobabbel/wordle/wordle"
+	"github.com/stretchr/testify/assert"
+)
+
+// finished returns the game of puzzle n, won at the given guess or lost when 0.
+func finished(t *testing.T, n, guesses int) *wordle.Status {
+	t.Helper()
+	game := &wordle.Status{Wordle: "CHAIR", PuzzleNumber: n}
+	for range 6 {
+		if guesses == game.Round+1 {
+			assert.NoError(t, game.Try("CHAIR"))
+			break
+		}
+		assert.NoError(t, game.Try("SCORE"))
+	}
+
+	return game
+}
+
+func TestStats(t *testing.T) {
+	tests := []struct {
+		name  string
+		games [][2]int
+		want  Stats
+	}{
+		{"no games", nil, Stats{}},
+		{
+			name:  "a winning streak",
+			games: [][2]int{{1, 3}, {2, 4}, {3, 3}},
+			want:  Stats{Played: 3, Won: 3, CurrentStreak: 3, MaxStreak: 3, Guesses: [6]int{0, 0, 2, 1}},
+		},
+		{
+			name:  "a lost game ends the streak",
+			games: [][2]int{{1, 1}, {2, 2}, {3, 0}, {4, 6}},
+			want:  Stats{Played: 4, Won: 3, CurrentStreak: 1, MaxStreak: 2, Guesses: [6]int{1, 1, 0, 0, 0, 1}},
+		},
+		{
+			name:  "a missed puzzle ends the streak",
+			games: [][2]int{{1, 2}, {2, 2}, {4, 2}},
+			want:  Stats{Played: 3, Won: 3, CurrentStreak: 1, MaxStreak: 2, Guesses: [6]int{0, 3}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var records []Record
+			for _, g := range test.games {
+				records = append(records, newRecord(finished(t, g[0], g[1])))
+			}
+
+			assert.Equal(t, test.want, stats(records))
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package status
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const lockFile = "lock"
+
+var ErrLocked = errors.New("the game is open in another wordle")
+
+// stopProcess asks the process holding a lock to quit.
+var stopProcess = stop
+
+// lock keeps other instances from playing the game of a directory at the
+// same time. It's released when the process exits, even if it crashes.
+type lock struct {
+	file *os.File
+}
+
+// Lock takes the lock of the game kept in dir, failing with ErrLocked
+// when another instance holds it.
+func Lock(dir string) (*lock, error) { //nolint: revive
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("error creating data directory: %v", err)
+	}
+	file, err := os.OpenFile(filepath.Join(dir, lockFile), os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("error opening lock file: %v", err)
+	}
+
+	if err := tryLock(file); err != nil {
+		file.Close() //nolint: errcheck
+		if errors.Is(err, errWouldBlock) {
+			if pid, err := holder(dir); err == nil {
+				return nil, fmt.Errorf("%w, process %d", ErrLocked, pid)
+			}
+			return nil, ErrLocked
+		}
+		return nil, fmt.Errorf("error locking the game: %v", err)
+	}
+
+	// The process id tells who holds the lock, so it can be taken over.
+	if err := file.Truncate(0); err == nil {
+		file.WriteString(strconv.Itoa(os.Getpid())) //nolint: errcheck
+	}
+
+	return &lock{file: file}, nil
+}
+
+// TakeOver asks the instance holding the lock of dir to quit, which saves
+// its game, and takes the lock once it's gone.
+func TakeOver(dir string, timeout time.Duration) (*lock, error) { //nolint: revive
+	l, err := Lock(dir)
+	if !errors.Is(err, ErrLocked) {
+		return l, err
+	}
+
+	pid, err := holder(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := stopProcess(pid); err != nil {
+		return nil, fmt.Errorf("error stopping process %d: %v", pid, err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		l, err := Lock(dir)
+		if !errors.Is(err, ErrLocked) || time.Now().After(deadline) {
+			return l, err
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// holder returns the id of the process holding the lock of dir.
+func holder(dir string) (int, error) {
+	file, err := os.Open(filepath.Join(dir, lockFile))
+	if err != nil {
+		return 0, fmt.Errorf("error reading lock file: %v", err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, 32))
+	if err != nil {
+		return 0, fmt.Errorf("error reading lock file: %v", err)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 {
+		return 0, fmt.Errorf("%w, unknown process", ErrLocked)
+	}
+
+	return pid, nil
+}
+
+// Unlock releases the lock, the lock file is kept so whoever waits on it
+// keeps locking the same file.
+func (l *lock) Unlock() error {
+	if err := unlock(l.file); err != nil {
+		l.file.Close() //nolint: errcheck
+		return fmt.Errorf("error unlocking the game: %v", err)
+	}
+
+	return l.file.Close()
+}
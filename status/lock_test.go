@@ -0,0 +1,73 @@
+package status
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLock(t *testing.T) {
+	t.Run("only one instance holds the lock", func(t *testing.T) {
+		dir := t.TempDir()
+		l, err := Lock(dir)
+		require.NoError(t, err)
+
+		_, err = Lock(dir)
+		assert.ErrorIs(t, err, ErrLocked)
+		assert.ErrorContains(t, err, fmt.Sprintf("process %d", os.Getpid()))
+
+		assert.NoError(t, l.Unlock())
+		l, err = Lock(dir)
+		require.NoError(t, err)
+		assert.NoError(t, l.Unlock())
+	})
+
+	t.Run("taking over stops the instance holding the lock", func(t *testing.T) {
+		dir := t.TempDir()
+		held, err := Lock(dir)
+		require.NoError(t, err)
+
+		var stopped int
+		stopProcess = func(pid int) error {
+			stopped = pid
+			go func() {
+				time.Sleep(100 * time.Millisecond)
+				held.Unlock() //nolint: errcheck
+			}()
+			return nil
+		}
+		t.Cleanup(func() { stopProcess = stop })
+
+		l, err := TakeOver(dir, time.Second)
+		require.NoError(t, err)
+		assert.Equal(t, os.Getpid(), stopped)
+		assert.NoError(t, l.Unlock())
+	})
+
+	t.Run("taking over fails when the instance doesn't quit", func(t *testing.T) {
+		dir := t.TempDir()
+		held, err := Lock(dir)
+		require.NoError(t, err)
+		defer held.Unlock() //nolint: errcheck
+
+		stopProcess = func(int) error { return nil }
+		t.Cleanup(func() { stopProcess = stop })
+		_, err = TakeOver(dir, 100*time.Millisecond)
+		assert.ErrorIs(t, err, ErrLocked)
+
+		stopProcess = func(int) error { return errors.New("no such process") }
+		_, err = TakeOver(dir, 100*time.Millisecond)
+		assert.EqualError(t, err, fmt.Sprintf("error stopping process %d: no such process", os.Getpid()))
+	})
+
+	t.Run("without a lock there's nothing to take over", func(t *testing.T) {
+		l, err := TakeOver(t.TempDir(), time.Second)
+		require.NoError(t, err)
+		assert.NoError(t, l.Unlock())
+	})
+}
@@ -0,0 +1,29 @@
+//go:build !windows
+
+package status
+
+import (
+	"os"
+	"syscall"
+)
+
+var errWouldBlock = syscall.EWOULDBLOCK
+
+func tryLock(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB) //nolint: gosec
+}
+
+func unlock(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_UN) //nolint: gosec
+}
+
+// stop sends pid the signal a terminal sends when it's closed, on which
+// the game is saved.
+func stop(pid int) error {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+
+	return p.Signal(syscall.SIGTERM)
+}
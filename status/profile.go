@@ -0,0 +1,181 @@
+package status
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+)
+
+const (
+	// DefaultProfile is kept in the data directory itself, so games
+	// played before profiles existed belong to it.
+	DefaultProfile = "default"
+	profilesDir    = "profiles"
+)
+
+var (
+	ErrInvalidProfile = errors.New("profile names have up to 32 letters, digits, - or _")
+	ErrProfileExists  = errors.New("profile already exists")
+	ErrUnknownProfile = errors.New("unknown profile")
+	ErrDefaultProfile = errors.New("the default profile can't be renamed nor deleted")
+)
+
+var profileName = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,32}$`)
+
+// profiles are the players sharing a data directory, each of them with
+// their own status, history and stats.
+type profiles struct {
+	dir     string
+	backend Backend
+}
+
+// Profiles manages the profiles in the data directory dir, whose games
+// are kept by backend.
+func Profiles(dir string, backend Backend) *profiles { //nolint: revive
+	return &profiles{dir: dir, backend: backend}
+}
+
+// Dir returns the directory the profile called name is stored in.
+func (p *profiles) Dir(name string) (string, error) {
+	if name == DefaultProfile {
+		return p.dir, nil
+	}
+	if !profileName.MatchString(name) {
+		return "", ErrInvalidProfile
+	}
+
+	dir := filepath.Join(p.dir, profilesDir, name)
+	if _, err := os.Stat(dir); err != nil {
+		return "", fmt.Errorf("%w %q, create it with 'wordle profile create %s'", ErrUnknownProfile, name, name)
+	}
+
+	return dir, nil
+}
+
+// List returns the names of the profiles, the default one first.
+func (p *profiles) List() ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(p.dir, profilesDir))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("error reading profiles: %v", err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() && profileName.MatchString(e.Name()) && e.Name() != DefaultProfile {
+			names = append(names, e.Name())
+		}
+	}
+	slices.Sort(names)
+
+	return append([]string{DefaultProfile}, names...), nil
+}
+
+func (p *profiles) Create(name string) error {
+	if err := p.available(name); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Join(p.dir, profilesDir, name), 0700); err != nil {
+		return fmt.Errorf("error creating profile: %v", err)
+	}
+
+	return nil
+}
+
+func (p *profiles) Rename(old, name string) error {
+	if old == DefaultProfile {
+		return ErrDefaultProfile
+	}
+	dir, err := p.Dir(old)
+	if err != nil {
+		return err
+	}
+	if err := p.available(name); err != nil {
+		return err
+	}
+	if err := os.Rename(dir, filepath.Join(p.dir, profilesDir, name)); err != nil {
+		return fmt.Errorf("error renaming profile: %v", err)
+	}
+
+	return nil
+}
+
+func (p *profiles) Delete(name string) error {
+	if name == DefaultProfile {
+		return ErrDefaultProfile
+	}
+	dir, err := p.Dir(name)
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("error deleting profile: %v", err)
+	}
+
+	return nil
+}
+
+// Merge adds the history of from to the one of into, and its game when
+// it's further ahead, sealed with the key of into so it can be resumed.
+// from is left as it was.
+func (p *profiles) Merge(from, into string) error {
+	fromDir, err := p.Dir(from)
+	if err != nil {
+		return err
+	}
+	intoDir, err := p.Dir(into)
+	if err != nil {
+		return err
+	}
+	if fromDir == intoDir {
+		return nil
+	}
+	src, err := p.backend(fromDir)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := p.backend(intoDir)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	records, err := src.History()
+	if err != nil {
+		return err
+	}
+	if err := dst.AddHistory(records...); err != nil {
+		return err
+	}
+
+	game, err := src.LoadGame()
+	if err != nil {
+		return err
+	}
+	current, err := dst.LoadGame()
+	if err != nil {
+		return err
+	}
+	if ahead(game, current) {
+		return dst.SaveGame(game)
+	}
+
+	return nil
+}
+
+func (p *profiles) available(name string) error {
+	if !profileName.MatchString(name) {
+		return ErrInvalidProfile
+	}
+	if name == DefaultProfile {
+		return fmt.Errorf("%w: %s", ErrProfileExists, name)
+	}
+	if _, err := os.Stat(filepath.Join(p.dir, profilesDir, name)); err == nil {
+		return fmt.Errorf("%w: %s", ErrProfileExists, name)
+	}
+
+	return nil
+}
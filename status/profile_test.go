@@ -0,0 +1,95 @@
+package status
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/Alvaroalonsobabbel/wordle/wordle"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfiles(t *testing.T) {
+	t.Run("create, list, rename and delete", func(t *testing.T) {
+		p := Profiles(t.TempDir(), JSON)
+		assert.NoError(t, p.Create("bob"))
+		assert.NoError(t, p.Create("alice"))
+		assert.ErrorIs(t, p.Create("alice"), ErrProfileExists)
+		assert.ErrorIs(t, p.Create("default"), ErrProfileExists)
+		assert.ErrorIs(t, p.Create("../x"), ErrInvalidProfile)
+
+		names, err := p.List()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"default", "alice", "bob"}, names)
+
+		assert.NoError(t, p.Rename("bob", "carol"))
+		assert.ErrorIs(t, p.Rename("bob", "dave"), ErrUnknownProfile)
+		assert.ErrorIs(t, p.Rename("carol", "alice"), ErrProfileExists)
+		assert.ErrorIs(t, p.Rename("default", "dave"), ErrDefaultProfile)
+
+		assert.NoError(t, p.Delete("alice"))
+		assert.ErrorIs(t, p.Delete("alice"), ErrUnknownProfile)
+		assert.ErrorIs(t, p.Delete("default"), ErrDefaultProfile)
+
+		names, err = p.List()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"default", "carol"}, names)
+	})
+
+	t.Run("profiles keep their own games", func(t *testing.T) {
+		dir := t.TempDir()
+		p := Profiles(dir, JSON)
+		assert.NoError(t, p.Create("alice"))
+
+		def, err := p.Dir(DefaultProfile)
+		assert.NoError(t, err)
+		assert.Equal(t, dir, def)
+		alice, err := p.Dir("alice")
+		assert.NoError(t, err)
+		assert.Equal(t, filepath.Join(dir, profilesDir, "alice"), alice)
+
+		assert.NoError(t, InDir(alice).SaveGame(finished(t, 1, 2)))
+		got, err := InDir(def).LoadGame()
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("merging", func(t *testing.T) {
+		p := Profiles(t.TempDir(), JSON)
+		assert.NoError(t, p.Create("alice"))
+		assert.NoError(t, p.Create("bob"))
+		dir := func(name string) string {
+			d, err := p.Dir(name)
+			assert.NoError(t, err)
+			return d
+		}
+		alice, bob := dir("alice"), dir("bob")
+
+		assert.NoError(t, InDir(alice).SaveGame(finished(t, 1, 3)))
+		assert.NoError(t, InDir(bob).SaveGame(finished(t, 1, 5)))
+		assert.NoError(t, InDir(bob).SaveGame(finished(t, 2, 2)))
+		inProgress := &wordle.Status{Wordle: "CHAIR", PuzzleNumber: 3}
+		assert.NoError(t, inProgress.Try("SCORE"))
+		assert.NoError(t, InDir(bob).SaveGame(inProgress))
+
+		assert.NoError(t, p.Merge("bob", "alice"))
+		history, err := InDir(alice).History()
+		assert.NoError(t, err)
+		assert.Len(t, history, 2)
+		assert.Equal(t, 3, history[0].Guesses, "the games of the profile merged into are kept")
+
+		game, err := InDir(alice).LoadGame()
+		assert.NoError(t, err)
+		assert.Equal(t, 3, game.PuzzleNumber, "the game further ahead is kept")
+		assert.Equal(t, 1, game.Round)
+		resumed := &wordle.Status{Wordle: "CHAIR", PuzzleNumber: 3}
+		wordle.WithSavedWordle(game)(resumed)
+		assert.Equal(t, 1, resumed.Round, "the merged game resumes with the key of the profile merged into")
+		assert.Equal(t, "CHAIR", resumed.Wordle)
+
+		bobGame, err := InDir(bob).LoadGame()
+		assert.NoError(t, err)
+		assert.Equal(t, 3, bobGame.PuzzleNumber, "the merged profile is left as it was")
+
+		assert.ErrorIs(t, p.Merge("carol", "alice"), ErrUnknownProfile)
+	})
+}
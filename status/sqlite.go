@@ -0,0 +1,197 @@
+package status
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Alvaroalonsobabbel/wordle/wordle"
+	_ "modernc.org/sqlite" // registers the sqlite driver
+)
+
+const dbFile = "wordle.db"
+
+const schema = `
+CREATE TABLE IF NOT EXISTS game (
+	id     INTEGER PRIMARY KEY CHECK (id = 1),
+	status TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS history (
+	puzzle_number INTEGER PRIMARY KEY,
+	wordle        TEXT NOT NULL,
+	won           INTEGER NOT NULL,
+	guesses       INTEGER NOT NULL,
+	hard_mode     INTEGER NOT NULL,
+	finished      TEXT NOT NULL
+);`
+
+// sqliteStore keeps the game and the history in a SQLite database, the
+// key games are sealed with is kept in a file next to it like the JSON
+// store does.
+type sqliteStore struct {
+	db    *sql.DB
+	files Storage
+}
+
+// SQLite keeps the game and the history in a SQLite database in dir.
+// A new database starts with the games kept in the JSON files of dir.
+func SQLite(dir string) (Store, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("error creating data directory: %v", err)
+	}
+	path := filepath.Join(dir, dbFile)
+	_, statErr := os.Stat(path)
+
+	db, err := sql.Open("sqlite", path+"?_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, fmt.Errorf("error opening database: %v", err)
+	}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close() //nolint: errcheck
+		return nil, fmt.Errorf("error creating database: %v", err)
+	}
+
+	s := &sqliteStore{db: db, files: Files(dir)}
+	if errors.Is(statErr, fs.ErrNotExist) {
+		if err := s.importJSON(InDir(dir)); err != nil {
+			db.Close() //nolint: errcheck
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+func (s *sqliteStore) importJSON(j *status) error {
+	records, err := j.History()
+	if err != nil {
+		return err
+	}
+	if err := s.AddHistory(records...); err != nil {
+		return err
+	}
+
+	game, err := j.LoadGame()
+	if err != nil || game == nil {
+		return err
+	}
+
+	return s.SaveGame(game)
+}
+
+func (s *sqliteStore) LoadGame() (*wordle.Status, error) {
+	var data string
+	err := s.db.QueryRow(`SELECT status FROM game WHERE id = 1`).Scan(&data)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error loading game: %v", err)
+	}
+
+	game := &wordle.Status{}
+	if err := json.Unmarshal([]byte(data), game); err != nil {
+		return nil, fmt.Errorf("error decoding game: %v", err)
+	}
+	if err := verify(s.files, game); err != nil {
+		return nil, err
+	}
+
+	return game, nil
+}
+
+func (s *sqliteStore) SaveGame(game *wordle.Status) error {
+	return saveGame(s, game, func() error {
+		if err := seal(s.files, game); err != nil {
+			return err
+		}
+		data, err := json.Marshal(game)
+		if err != nil {
+			return fmt.Errorf("error encoding game: %v", err)
+		}
+
+		_, err = s.db.Exec(`INSERT INTO game (id, status) VALUES (1, ?)
+			ON CONFLICT (id) DO UPDATE SET status = excluded.status`, string(data))
+		if err != nil {
+			return fmt.Errorf("error saving game: %v", err)
+		}
+
+		return nil
+	})
+}
+
+func (s *sqliteStore) RemoveGame() error {
+	if _, err := s.db.Exec(`DELETE FROM game`); err != nil {
+		return fmt.Errorf("error removing game: %v", err)
+	}
+
+	return nil
+}
+
+func (s *sqliteStore) History() ([]Record, error) {
+	rows, err := s.db.Query(`SELECT puzzle_number, wordle, won, guesses, hard_mode, finished
+		FROM history ORDER BY puzzle_number`)
+	if err != nil {
+		return nil, fmt.Errorf("error reading history: %v", err)
+	}
+	defer rows.Close()
+
+	var records []Record
+	for rows.Next() {
+		var (
+			r        Record
+			finished string
+		)
+		if err := rows.Scan(&r.PuzzleNumber, &r.Wordle, &r.Won, &r.Guesses, &r.HardMode, &finished); err != nil {
+			return nil, fmt.Errorf("error reading history: %v", err)
+		}
+		if r.Finished, err = time.Parse(time.RFC3339Nano, finished); err != nil {
+			return nil, fmt.Errorf("error reading history: %v", err)
+		}
+		records = append(records, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading history: %v", err)
+	}
+
+	return records, nil
+}
+
+func (s *sqliteStore) Stats() (Stats, error) {
+	records, err := s.History()
+	if err != nil {
+		return Stats{}, err
+	}
+
+	return stats(records), nil
+}
+
+func (s *sqliteStore) AddHistory(records ...Record) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error adding history: %v", err)
+	}
+	defer tx.Rollback() //nolint: errcheck
+
+	for _, r := range records {
+		_, err := tx.Exec(`INSERT OR IGNORE INTO history (puzzle_number, wordle, won, guesses, hard_mode, finished)
+			VALUES (?, ?, ?, ?, ?, ?)`, r.PuzzleNumber, r.Wordle, r.Won, r.Guesses, r.HardMode, r.Finished.UTC().Format(time.RFC3339Nano))
+		if err != nil {
+			return fmt.Errorf("error adding history: %v", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error adding history: %v", err)
+	}
+
+	return nil
+}
+
+func (s *sqliteStore) Close() error {
+	return s.db.Close()
+}
@@ -1,40 +1,50 @@
 package status
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"os"
-	"path/filepath"
+	"io/fs"
+	"strings"
 
 	"github.com/Alvaroalonsobabbel/wordle/wordle"
 )
 
 const (
-	statusFile = ".wordle"
-	read       = os.O_RDONLY
-	write      = os.O_CREATE | os.O_RDWR | os.O_TRUNC
+	statusFile = "status.json"
+	keyFile    = "key"
+	keySize    = 32
 )
 
-type fileOpener interface {
-	file(int) (io.ReadWriteCloser, error)
-}
+var ErrInvalidKey = errors.New("invalid key file, the games it signed can't be verified without it")
 
 type status struct {
-	open fileOpener
+	store Storage
+}
+
+// New keeps the status in s.
+func New(s Storage) *status { //nolint: revive
+	return &status{store: s}
 }
 
+// Game keeps the status in the data directory.
 func Game() *status { //nolint: revive
-	return &status{
-		open: &opener{},
-	}
+	return New(Files(""))
+}
+
+// InDir stores the status in dir instead of the default directory,
+// i.e. to keep a separate status for each player.
+func InDir(dir string) *status { //nolint: revive
+	return New(Files(dir))
 }
 
-func (s *status) Load() (*wordle.Status, error) {
-	file, err := s.open.file(read)
+func (s *status) LoadGame() (*wordle.Status, error) {
+	file, err := s.store.Open(statusFile)
 	if err != nil {
-		if os.IsNotExist(err) {
+		if errors.Is(err, fs.ErrNotExist) {
 			return nil, nil
 		}
 
@@ -51,57 +61,114 @@ func (s *status) Load() (*wordle.Status, error) {
 		return nil, fmt.Errorf("error decoding wordle status into file: %v", err)
 	}
 
+	if err := verify(s.store, status); err != nil {
+		return nil, err
+	}
+
 	return status, nil
 }
 
-func (s *status) Save(status *wordle.Status) error {
-	file, err := s.open.file(write)
+func (s *status) SaveGame(status *wordle.Status) error {
+	return saveGame(s, status, func() error {
+		if err := seal(s.store, status); err != nil {
+			return err
+		}
+
+		file, err := s.store.Create(statusFile)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		if err := json.NewEncoder(file).Encode(status); err != nil {
+			return fmt.Errorf("error encoding wordle status into file: %v", err)
+		}
+
+		return file.Close()
+	})
+}
+
+func (s *status) Close() error {
+	return nil
+}
+
+// Key returns the per-install key used to sign saved games and shared
+// results. It's created the first time it's needed.
+func (s *status) Key() ([]byte, error) {
+	return loadKey(s.store)
+}
+
+// seal signs the game with the key kept in st.
+func seal(st Storage, game *wordle.Status) error {
+	key, err := loadKey(st)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
+	game.Seal(key)
+
+	return nil
+}
 
-	if err := json.NewEncoder(file).Encode(status); err != nil {
-		return fmt.Errorf("error encoding wordle status into file: %v", err)
+// verify checks the game wasn't modified since it was sealed with the key kept in st.
+func verify(st Storage, game *wordle.Status) error {
+	key, err := loadKey(st)
+	if err != nil {
+		return err
+	}
+	if err := game.Verify(key); err != nil {
+		return fmt.Errorf("invalid status file, remove it with -rmstatus: %w", err)
 	}
 
 	return nil
 }
 
-type opener struct{}
-
-func (opener) file(mode int) (io.ReadWriteCloser, error) {
-	homeDir, err := os.UserHomeDir()
+// loadKey reads the key kept in st, creating it when there's none. A key
+// that can't be read is never replaced, the games sealed with it would
+// fail to verify.
+func loadKey(st Storage) ([]byte, error) {
+	file, err := st.Open(keyFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return newKey(st)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("error getting home directory: %v", err)
+		return nil, err
 	}
+	defer file.Close()
 
-	file, err := os.OpenFile(filepath.Join(homeDir, statusFile), mode, 0644)
+	data, err := io.ReadAll(file)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, err
-		}
-		return nil, fmt.Errorf("error opening status file: %v", err)
+		return nil, fmt.Errorf("error reading key file: %v", err)
+	}
+	key, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(key) != keySize {
+		return nil, ErrInvalidKey
 	}
 
-	return file, nil
+	return key, nil
 }
 
-func Remove(string) error {
-	// Remove takes a string and it's not used since it
-	// has to match the fn signature for flag.BoolFunc
-	homeDir, err := os.UserHomeDir()
+func newKey(st Storage) ([]byte, error) {
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("error generating key: %v", err)
+	}
+
+	file, err := st.Create(keyFile)
 	if err != nil {
-		return fmt.Errorf("error getting home directory: %v", err)
+		return nil, err
 	}
+	defer file.Close()
 
-	err = os.Remove(filepath.Join(homeDir, statusFile))
-	if err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("error deleting file: %v", err)
+	if _, err := io.WriteString(file, hex.EncodeToString(key)); err != nil {
+		return nil, fmt.Errorf("error writing key file: %v", err)
+	}
+	if err := file.Close(); err != nil {
+		return nil, err
 	}
 
-	fmt.Println("Status file removed.")
-	os.Exit(0)
+	return key, nil
+}
 
-	return nil
+func (s *status) RemoveGame() error {
+	return s.store.Remove(statusFile)
 }
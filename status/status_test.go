@@ -1,72 +1,221 @@
 package status
 
 import (
+	"bytes"
 	"io"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/Alvaroalonsobabbel/wordle/wordle"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
-type mockFile struct {
-	data []byte
+// mockStorage keeps the files in memory.
+type mockStorage struct {
+	files map[string][]byte
 }
 
-func (m *mockFile) Read(p []byte) (n int, err error) {
-	if len(m.data) == 0 {
-		return 0, io.EOF
+func newMockStorage(status string) *mockStorage {
+	m := &mockStorage{files: map[string][]byte{keyFile: []byte(testKey)}}
+	if status != "" {
+		m.files[statusFile] = []byte(status)
 	}
-	n = copy(p, m.data)
-	m.data = m.data[n:]
-	return n, nil
+
+	return m
 }
 
-func (m *mockFile) Write(p []byte) (n int, err error) {
-	m.data = append(m.data, p...)
+func (m *mockStorage) Open(name string) (io.ReadCloser, error) {
+	data, ok := m.files[name]
+	if !ok {
+		return nil, fs.ErrNotExist
+	}
 
-	return len(p), nil
+	return io.NopCloser(bytes.NewReader(data)), nil
 }
 
-func (m *mockFile) Close() error {
+func (m *mockStorage) Create(name string) (io.WriteCloser, error) {
+	return &mockFile{name: name, storage: m}, nil
+}
+
+func (m *mockStorage) Remove(name string) error {
+	delete(m.files, name)
 	return nil
 }
 
-type mockOpener struct {
-	f *mockFile
+// mockFile stores what's written once it's closed.
+type mockFile struct {
+	bytes.Buffer
+	name    string
+	storage *mockStorage
 }
 
-func (m *mockOpener) file(int) (io.ReadWriteCloser, error) {
-	return m.f, nil
+func (m *mockFile) Close() error {
+	m.storage.files[m.name] = m.Bytes()
+	return nil
 }
 
+const testKey = "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"
+
 func TestLoadGame(t *testing.T) {
 	t.Run("when status file has content, a new wordle.Status struct is returned", func(t *testing.T) {
-		mockFile := &mockFile{data: []byte(`{"round":4,"puzzle_number":1197,"wordle":"BRAIN","hard_mode":true,"results":[],"discovered":[],"hints":[]}`)}
-		status := &status{open: &mockOpener{f: mockFile}}
+		status := New(newMockStorage(`{"round":4,"puzzle_number":1197,"wordle":"BRAIN","hard_mode":true,"results":[],"discovered":[],"hints":[]}`))
 
-		wordle, err := status.Load()
+		wordle, err := status.LoadGame()
 		assert.NoError(t, err)
 		assert.Equal(t, 1197, wordle.PuzzleNumber)
+		assert.Equal(t, "BRAIN", wordle.Wordle)
 		assert.True(t, wordle.HardMode)
+		assert.True(t, wordle.Unverified, "games saved before they were signed can't be vouched for")
+	})
+
+	t.Run("when status file has a game in progress, the answer is not loaded", func(t *testing.T) {
+		status := New(newMockStorage(""))
+		game := &wordle.Status{Wordle: "BRAIN", PuzzleNumber: 1197}
+		assert.NoError(t, game.Try("CHAIR"))
+		assert.NoError(t, status.SaveGame(game))
+
+		wordle, err := status.LoadGame()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, wordle.Round)
+		assert.Empty(t, wordle.Wordle)
 	})
 
 	t.Run("when status file is empty, nil wordle.Status is returned", func(t *testing.T) {
-		mockFile := &mockFile{data: []byte(``)}
-		status := &status{open: &mockOpener{f: mockFile}}
-		wordle, err := status.Load()
+		storage := newMockStorage("")
+		storage.files[statusFile] = nil
+		status := New(storage)
+		wordle, err := status.LoadGame()
+		assert.NoError(t, err)
+		assert.Nil(t, wordle)
+	})
+
+	t.Run("when there is no status file, nil wordle.Status is returned", func(t *testing.T) {
+		wordle, err := New(newMockStorage("")).LoadGame()
 		assert.NoError(t, err)
 		assert.Nil(t, wordle)
 	})
 }
 
 func TestSaveGame(t *testing.T) {
-	mockFile := &mockFile{}
+	storage := newMockStorage("")
 	wordle := &wordle.Status{Wordle: "CHAIR", HardMode: true}
-	status := &status{open: &mockOpener{f: mockFile}}
+	status := New(storage)
 
-	err := status.Save(wordle)
+	err := status.SaveGame(wordle)
 	assert.NoError(t, err)
-	want := `{"round":0,"puzzle_number":0,"wordle":"CHAIR","hard_mode":true,"results":null,"discovered":[0,0,0,0,0],"hints":null,"used":null}
+	want := `{"round":0,"puzzle_number":0,"hard_mode":true,"results":null,"discovered":[0,0,0,0,0],"hints":null,"used":null,"mac":"` + wordle.MAC + `","wordle_hash":"abf23437c1282b5146ce2cd182a06ae073912c129be2a155a17d0a35d8f18275"}
 `
-	assert.Equal(t, want, string(mockFile.data))
+	assert.Equal(t, want, string(storage.files[statusFile]))
+	assert.NotEmpty(t, wordle.MAC)
+}
+
+func TestIntegrity(t *testing.T) {
+	t.Run("a saved game loads back", func(t *testing.T) {
+		status := New(newMockStorage(""))
+		game := &wordle.Status{Wordle: "CHAIR", PuzzleNumber: 1}
+		assert.NoError(t, game.Try("SCORE"))
+		assert.NoError(t, status.SaveGame(game))
+
+		got, err := status.LoadGame()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, got.Round)
+	})
+
+	t.Run("a tampered game returns an error", func(t *testing.T) {
+		storage := newMockStorage("")
+		status := New(storage)
+		game := &wordle.Status{Wordle: "CHAIR", PuzzleNumber: 1}
+		assert.NoError(t, game.Try("SCORE"))
+		assert.NoError(t, status.SaveGame(game))
+		storage.files[statusFile] = []byte(strings.Replace(string(storage.files[statusFile]), `"round":1`, `"round":2`, 1))
+
+		_, err := status.LoadGame()
+		assert.ErrorIs(t, err, wordle.ErrTampered)
+	})
+
+	t.Run("a game stripped of its signature returns an error", func(t *testing.T) {
+		storage := newMockStorage("")
+		status := New(storage)
+		game := &wordle.Status{Wordle: "CHAIR", PuzzleNumber: 1}
+		assert.NoError(t, game.Try("SCORE"))
+		assert.NoError(t, status.SaveGame(game))
+		stripped := regexp.MustCompile(`"mac":"[0-9a-f]+",`).ReplaceAllString(string(storage.files[statusFile]), "")
+		require.Contains(t, stripped, `{"67":2}`)
+		storage.files[statusFile] = []byte(strings.Replace(stripped, `{"67":2}`, `{"67":1}`, 1))
+
+		_, err := status.LoadGame()
+		assert.ErrorIs(t, err, wordle.ErrTampered)
+	})
+
+	t.Run("a forged game in the unsigned format is never vouched for", func(t *testing.T) {
+		storage := newMockStorage("")
+		status := New(storage)
+		game := &wordle.Status{Wordle: "CHAIR", PuzzleNumber: 1}
+		for _, try := range []string{"SCORE", "CLOUD", "CHAIN", "CHAMP", "CHAOS", "CHART"} {
+			assert.NoError(t, game.Try(try))
+		}
+		assert.NoError(t, status.SaveGame(game))
+		// The lost game is turned into a win.
+		stripped := regexp.MustCompile(`"mac":"[0-9a-f]+",`).ReplaceAllString(string(storage.files[statusFile]), "")
+		require.Contains(t, stripped, `"discovered":[67,72,65,73,0]`)
+		storage.files[statusFile] = []byte(strings.Replace(stripped, `"discovered":[67,72,65,73,0]`, `"discovered":[67,72,65,73,82]`, 1))
+
+		forged, err := status.LoadGame()
+		require.NoError(t, err)
+		assert.True(t, forged.Unverified)
+		assert.NoError(t, status.SaveGame(forged))
+
+		saved, err := status.LoadGame()
+		require.NoError(t, err)
+		key, err := status.Key()
+		require.NoError(t, err)
+		assert.ErrorIs(t, saved.VerifyShare(saved.Share(), key), wordle.ErrUnverifiable, "signing it again doesn't vouch for it")
+	})
+
+	t.Run("a corrupt key is never replaced", func(t *testing.T) {
+		storage := newMockStorage("")
+		storage.files[keyFile] = []byte("not a key")
+		status := New(storage)
+
+		_, err := status.Key()
+		assert.ErrorIs(t, err, ErrInvalidKey)
+		assert.ErrorIs(t, status.SaveGame(&wordle.Status{Wordle: "CHAIR"}), ErrInvalidKey)
+		assert.Equal(t, "not a key", string(storage.files[keyFile]))
+	})
+
+	t.Run("a key is created when there is none", func(t *testing.T) {
+		storage := newMockStorage("")
+		delete(storage.files, keyFile)
+		status := New(storage)
+
+		key, err := status.Key()
+		assert.NoError(t, err)
+		assert.Len(t, key, keySize)
+
+		again, err := status.Key()
+		assert.NoError(t, err)
+		assert.Equal(t, key, again)
+	})
+}
+
+func TestInDir(t *testing.T) {
+	dir := t.TempDir()
+	game := &wordle.Status{Wordle: "CHAIR", PuzzleNumber: 1}
+	assert.NoError(t, game.Try("SCORE"))
+
+	assert.NoError(t, InDir(dir).SaveGame(game))
+	assert.FileExists(t, filepath.Join(dir, statusFile))
+	assert.FileExists(t, filepath.Join(dir, keyFile))
+
+	got, err := InDir(dir).LoadGame()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, got.Round)
+
+	got, err = InDir(t.TempDir()).LoadGame()
+	assert.NoError(t, err)
+	assert.Nil(t, got)
 }
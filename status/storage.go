@@ -0,0 +1,188 @@
+package status
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+const (
+	// homeEnv overrides the directory where wordle stores its files.
+	homeEnv = "WORDLE_HOME"
+	appDir  = "wordle"
+
+	// QueueFile is where the games that couldn't be posted to the
+	// leaderboard wait to be posted.
+	QueueFile = "queue.json"
+)
+
+// legacyFiles are the files kept in the home directory by older versions
+// and the name they have in the data directory.
+var legacyFiles = map[string]string{
+	".wordle":       statusFile,
+	".wordle_key":   keyFile,
+	".wordle_queue": QueueFile,
+}
+
+// Storage holds the files the status is kept in.
+type Storage interface {
+	// Open opens name for reading, the error wraps fs.ErrNotExist
+	// when there's no such file.
+	Open(name string) (io.ReadCloser, error)
+	// Create replaces name with what's written to it once it's closed.
+	Create(name string) (io.WriteCloser, error)
+	// Remove deletes name, it's not an error when there's no such file.
+	Remove(name string) error
+}
+
+// Files stores the files in dir, the data directory when empty.
+func Files(dir string) Storage {
+	return files{dir: dir}
+}
+
+type files struct {
+	dir string
+}
+
+func (f files) path(name string) (string, error) {
+	if f.dir != "" {
+		return filepath.Join(f.dir, name), nil
+	}
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, name), nil
+}
+
+func (f files) Open(name string) (io.ReadCloser, error) {
+	path, err := f.path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("error opening %s file: %v", name, err)
+	}
+
+	return file, nil
+}
+
+func (f files) Create(name string) (io.WriteCloser, error) {
+	path, err := f.path(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("error creating data directory: %v", err)
+	}
+
+	return createAtomic(path)
+}
+
+func (f files) Remove(name string) error {
+	path, err := f.path(name)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("error deleting %s file: %v", name, err)
+	}
+
+	return nil
+}
+
+// Dir is the directory where the status and the rest of the files
+// wordle needs are stored: $WORDLE_HOME when set, otherwise the wordle
+// directory in the user data directory, i.e. ~/.local/share/wordle.
+func Dir() (string, error) {
+	if dir := os.Getenv(homeEnv); dir != "" {
+		return dir, nil
+	}
+
+	data, err := dataHome()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(data, appDir), nil
+}
+
+// dataHome is where user data goes: $XDG_DATA_HOME or the platform
+// equivalent.
+func dataHome() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); filepath.IsAbs(dir) {
+		return dir, nil
+	}
+	if dir := os.Getenv("LocalAppData"); runtime.GOOS == "windows" && dir != "" {
+		return dir, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("error getting home directory: %v", err)
+	}
+	if runtime.GOOS == "darwin" {
+		return filepath.Join(home, "Library", "Application Support"), nil
+	}
+
+	return filepath.Join(home, ".local", "share"), nil
+}
+
+// Migrate moves the files older versions kept in from, the home
+// directory or the directory given to InDir, to the to directory unless
+// it already has them.
+func Migrate(from, to string) error {
+	for old, name := range legacyFiles {
+		src, dst := filepath.Join(from, old), filepath.Join(to, name)
+		if _, err := os.Stat(src); err != nil {
+			continue
+		}
+		if _, err := os.Stat(dst); err == nil {
+			continue
+		}
+		if err := os.MkdirAll(to, 0700); err != nil {
+			return fmt.Errorf("error creating data directory: %v", err)
+		}
+		if err := move(src, dst); err != nil {
+			return fmt.Errorf("error moving %s to %s: %v", src, to, err)
+		}
+	}
+
+	return nil
+}
+
+// move renames src to dst, copying it when they're in different devices.
+func move(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	f, err := createAtomic(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.discard()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Remove(src)
+}
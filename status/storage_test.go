@@ -0,0 +1,97 @@
+package status
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/Alvaroalonsobabbel/wordle/wordle"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDir(t *testing.T) {
+	t.Run("WORDLE_HOME takes precedence", func(t *testing.T) {
+		t.Setenv(homeEnv, "/tmp/wordle")
+		t.Setenv("XDG_DATA_HOME", "/tmp/data")
+		dir, err := Dir()
+		assert.NoError(t, err)
+		assert.Equal(t, "/tmp/wordle", dir)
+	})
+
+	t.Run("in the XDG data directory", func(t *testing.T) {
+		t.Setenv(homeEnv, "")
+		t.Setenv("XDG_DATA_HOME", "/tmp/data")
+		dir, err := Dir()
+		assert.NoError(t, err)
+		assert.Equal(t, filepath.Join("/tmp/data", appDir), dir)
+	})
+
+	t.Run("relative XDG data directories are ignored", func(t *testing.T) {
+		if runtime.GOOS != "linux" {
+			t.Skip("the platform data directory is not ~/.local/share")
+		}
+		home := t.TempDir()
+		t.Setenv("HOME", home)
+		t.Setenv(homeEnv, "")
+		t.Setenv("XDG_DATA_HOME", "data")
+		dir, err := Dir()
+		assert.NoError(t, err)
+		assert.Equal(t, filepath.Join(home, ".local", "share", appDir), dir)
+	})
+}
+
+func TestFiles(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "wordle")
+	status := New(Files(dir))
+	game := &wordle.Status{Wordle: "CHAIR", PuzzleNumber: 1}
+
+	assert.NoError(t, status.SaveGame(game), "the directory is created")
+	assert.FileExists(t, filepath.Join(dir, statusFile))
+
+	assert.NoError(t, status.RemoveGame())
+	assert.NoFileExists(t, filepath.Join(dir, statusFile))
+	assert.NoError(t, status.RemoveGame(), "removing a missing file")
+
+	_, err := Files(dir).Open(statusFile)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestMigrate(t *testing.T) {
+	t.Run("moves the files from the home directory", func(t *testing.T) {
+		home, dir := t.TempDir(), filepath.Join(t.TempDir(), "wordle")
+		for _, name := range []string{".wordle", ".wordle_key", ".wordle_queue"} {
+			assert.NoError(t, os.WriteFile(filepath.Join(home, name), []byte(name), 0600))
+		}
+
+		assert.NoError(t, Migrate(home, dir))
+		for old, name := range map[string]string{".wordle": statusFile, ".wordle_key": keyFile, ".wordle_queue": QueueFile} {
+			assert.NoFileExists(t, filepath.Join(home, old))
+			got, err := os.ReadFile(filepath.Join(dir, name))
+			assert.NoError(t, err)
+			assert.Equal(t, old, string(got))
+		}
+	})
+
+	t.Run("existing files are kept", func(t *testing.T) {
+		home, dir := t.TempDir(), t.TempDir()
+		assert.NoError(t, os.WriteFile(filepath.Join(home, ".wordle"), []byte("old"), 0600))
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, statusFile), []byte("new"), 0600))
+
+		assert.NoError(t, Migrate(home, dir))
+		got, err := os.ReadFile(filepath.Join(dir, statusFile))
+		assert.NoError(t, err)
+		assert.Equal(t, "new", string(got))
+		assert.FileExists(t, filepath.Join(home, ".wordle"))
+	})
+
+	t.Run("within the same directory", func(t *testing.T) {
+		dir := t.TempDir()
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, ".wordle"), []byte("old"), 0600))
+
+		assert.NoError(t, Migrate(dir, dir))
+		assert.FileExists(t, filepath.Join(dir, statusFile))
+		assert.NoFileExists(t, filepath.Join(dir, ".wordle"))
+	})
+}
@@ -0,0 +1,99 @@
+package status
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Alvaroalonsobabbel/wordle/wordle"
+)
+
+var ErrUnknownBackend = errors.New("unknown storage backend")
+
+// Store keeps the games of a player: the one being played and the
+// history of the finished ones.
+type Store interface {
+	// LoadGame returns the saved game, nil when there's none.
+	LoadGame() (*wordle.Status, error)
+	// SaveGame saves the game, which is added to the history once it's
+	// finished. A saved game further ahead, i.e. by another instance, is kept.
+	SaveGame(*wordle.Status) error
+	// RemoveGame deletes the saved game, the history is kept.
+	RemoveGame() error
+	// History returns the finished games sorted by puzzle number.
+	History() ([]Record, error)
+	Stats() (Stats, error)
+	// AddHistory adds the records of the puzzles not in the history yet.
+	AddHistory(...Record) error
+	Close() error
+}
+
+// Backend opens the store kept in a directory.
+type Backend func(dir string) (Store, error)
+
+var backends = map[string]Backend{
+	"json":   JSON,
+	"sqlite": SQLite,
+}
+
+// JSON keeps the game and the history in JSON files.
+func JSON(dir string) (Store, error) {
+	return InDir(dir), nil
+}
+
+// GetBackend returns the backend called name, JSON files when empty.
+func GetBackend(name string) (Backend, error) {
+	if name == "" {
+		return JSON, nil
+	}
+	if b, ok := backends[name]; ok {
+		return b, nil
+	}
+
+	names := make([]string, 0, len(backends))
+	for n := range backends {
+		names = append(names, n)
+	}
+	slices.Sort(names)
+
+	return nil, fmt.Errorf("%w %q, choose one of %s", ErrUnknownBackend, name, strings.Join(names, ", "))
+}
+
+// saveGame saves game with write and records it once it's finished. When
+// a game further ahead was saved by another instance, only the result of
+// this one is kept.
+func saveGame(s Store, game *wordle.Status, write func() error) error {
+	if current, err := s.LoadGame(); err == nil && ahead(current, game) {
+		return record(s, game)
+	}
+	if err := write(); err != nil {
+		return err
+	}
+
+	return record(s, game)
+}
+
+// ahead reports whether game is of a later puzzle than current, or has
+// more guesses in the same one.
+func ahead(game, current *wordle.Status) bool {
+	switch {
+	case game == nil:
+		return false
+	case current == nil:
+		return true
+	case game.PuzzleNumber != current.PuzzleNumber:
+		return game.PuzzleNumber > current.PuzzleNumber
+	default:
+		return game.Round > current.Round
+	}
+}
+
+// record keeps game in the history of s once it's finished.
+func record(s Store, game *wordle.Status) error {
+	if !game.Finish() {
+		return nil
+	}
+
+	return s.AddHistory(newRecord(game))
+}
@@ -0,0 +1,214 @@
+package status
+
+import (
+	"testing"
+
+	"github.com/Alvaroalonsobabbel/wordle/wordle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStores runs the same tests against every backend.
+func TestStores(t *testing.T) {
+	for name, backend := range backends {
+		t.Run(name, func(t *testing.T) {
+			testStore(t, func(t *testing.T, dir string) Store {
+				t.Helper()
+				s, err := backend(dir)
+				require.NoError(t, err)
+				t.Cleanup(func() { s.Close() }) //nolint: errcheck
+				return s
+			})
+		})
+	}
+}
+
+func testStore(t *testing.T, open func(*testing.T, string) Store) {
+	t.Run("there is no game at first", func(t *testing.T) {
+		s := open(t, t.TempDir())
+		game, err := s.LoadGame()
+		assert.NoError(t, err)
+		assert.Nil(t, game)
+		history, err := s.History()
+		assert.NoError(t, err)
+		assert.Empty(t, history)
+	})
+
+	t.Run("a saved game loads back without its answer", func(t *testing.T) {
+		s := open(t, t.TempDir())
+		game := &wordle.Status{Wordle: "CHAIR", PuzzleNumber: 1, HardMode: true}
+		assert.NoError(t, game.Try("SCORE"))
+		assert.NoError(t, s.SaveGame(game))
+
+		got, err := s.LoadGame()
+		require.NoError(t, err)
+		assert.Equal(t, 1, got.Round)
+		assert.Equal(t, 1, got.PuzzleNumber)
+		assert.True(t, got.HardMode)
+		assert.Empty(t, got.Wordle)
+		assert.Equal(t, game.Results, got.Results)
+	})
+
+	t.Run("saving replaces the game", func(t *testing.T) {
+		dir := t.TempDir()
+		s := open(t, dir)
+		game := &wordle.Status{Wordle: "CHAIR", PuzzleNumber: 1}
+		assert.NoError(t, game.Try("SCORE"))
+		assert.NoError(t, s.SaveGame(game))
+		assert.NoError(t, game.Try("CHAIR"))
+		assert.NoError(t, s.SaveGame(game))
+
+		got, err := open(t, dir).LoadGame()
+		require.NoError(t, err)
+		assert.Equal(t, 2, got.Round)
+		assert.Equal(t, "CHAIR", got.Wordle, "finished games keep their answer")
+	})
+
+	t.Run("a game further ahead is never replaced", func(t *testing.T) {
+		dir := t.TempDir()
+		s, other := open(t, dir), open(t, dir)
+		stale := &wordle.Status{Wordle: "CHAIR", PuzzleNumber: 1}
+		assert.NoError(t, stale.Try("SCORE"))
+		game := &wordle.Status{Wordle: "CHAIR", PuzzleNumber: 1}
+		assert.NoError(t, game.Try("SCORE"))
+		assert.NoError(t, game.Try("CLOUD"))
+
+		assert.NoError(t, other.SaveGame(game))
+		assert.NoError(t, s.SaveGame(stale))
+		got, err := s.LoadGame()
+		require.NoError(t, err)
+		assert.Equal(t, 2, got.Round, "the game with more guesses is kept")
+
+		assert.NoError(t, s.SaveGame(&wordle.Status{Wordle: "PIANO", PuzzleNumber: 2}))
+		got, err = other.LoadGame()
+		require.NoError(t, err)
+		assert.Equal(t, 2, got.PuzzleNumber, "the next puzzle replaces it")
+
+		assert.NoError(t, stale.Try("CHAIR"))
+		assert.NoError(t, s.SaveGame(stale))
+		got, err = s.LoadGame()
+		require.NoError(t, err)
+		assert.Equal(t, 2, got.PuzzleNumber)
+		history, err := s.History()
+		require.NoError(t, err)
+		require.Len(t, history, 1, "the result of a game behind is still kept")
+		assert.Equal(t, 1, history[0].PuzzleNumber)
+	})
+
+	t.Run("removing the game keeps the history", func(t *testing.T) {
+		s := open(t, t.TempDir())
+		assert.NoError(t, s.RemoveGame(), "removing a missing game")
+		assert.NoError(t, s.SaveGame(finished(t, 1, 2)))
+		assert.NoError(t, s.RemoveGame())
+
+		game, err := s.LoadGame()
+		assert.NoError(t, err)
+		assert.Nil(t, game)
+		history, err := s.History()
+		assert.NoError(t, err)
+		assert.Len(t, history, 1)
+	})
+
+	t.Run("finished games are recorded once", func(t *testing.T) {
+		s := open(t, t.TempDir())
+		game := &wordle.Status{Wordle: "CHAIR", PuzzleNumber: 1}
+		assert.NoError(t, game.Try("SCORE"))
+		assert.NoError(t, s.SaveGame(game))
+		history, err := s.History()
+		assert.NoError(t, err)
+		assert.Empty(t, history, "games in progress are not recorded")
+
+		assert.NoError(t, game.Try("CHAIR"))
+		assert.NoError(t, s.SaveGame(game))
+		assert.NoError(t, s.SaveGame(game))
+		assert.NoError(t, s.SaveGame(finished(t, 3, 0)))
+
+		history, err = s.History()
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+		assert.Equal(t, Record{PuzzleNumber: 1, Wordle: "CHAIR", Won: true, Guesses: 2, Finished: history[0].Finished}, history[0])
+		assert.False(t, history[1].Won)
+		assert.False(t, history[0].Finished.IsZero())
+	})
+
+	t.Run("the history is sorted by puzzle number", func(t *testing.T) {
+		s := open(t, t.TempDir())
+		for _, n := range []int{5, 2, 9} {
+			assert.NoError(t, s.SaveGame(finished(t, n, 1)))
+		}
+
+		history, err := s.History()
+		require.NoError(t, err)
+		require.Len(t, history, 3)
+		for i, n := range []int{2, 5, 9} {
+			assert.Equal(t, n, history[i].PuzzleNumber)
+		}
+	})
+
+	t.Run("adding history keeps the puzzles already played", func(t *testing.T) {
+		s := open(t, t.TempDir())
+		assert.NoError(t, s.SaveGame(finished(t, 1, 2)))
+		assert.NoError(t, s.AddHistory(newRecord(finished(t, 1, 6)), newRecord(finished(t, 2, 0))))
+
+		history, err := s.History()
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+		assert.Equal(t, 2, history[0].Guesses)
+		assert.Equal(t, 2, history[1].PuzzleNumber)
+	})
+
+	t.Run("stats", func(t *testing.T) {
+		s := open(t, t.TempDir())
+		for _, g := range [][2]int{{1, 3}, {2, 0}, {3, 4}, {4, 4}} {
+			assert.NoError(t, s.SaveGame(finished(t, g[0], g[1])))
+		}
+
+		got, err := s.Stats()
+		assert.NoError(t, err)
+		assert.Equal(t, Stats{Played: 4, Won: 3, CurrentStreak: 2, MaxStreak: 2, Guesses: [6]int{0, 0, 1, 2}}, got)
+	})
+}
+
+func TestGetBackend(t *testing.T) {
+	b, err := GetBackend("")
+	assert.NoError(t, err)
+	assert.NotNil(t, b)
+
+	_, err = GetBackend("postgres")
+	assert.EqualError(t, err, `unknown storage backend "postgres", choose one of json, sqlite`)
+}
+
+func TestSQLite(t *testing.T) {
+	t.Run("a new database starts with the JSON games", func(t *testing.T) {
+		dir := t.TempDir()
+		assert.NoError(t, InDir(dir).SaveGame(finished(t, 1, 2)))
+		game := &wordle.Status{Wordle: "CHAIR", PuzzleNumber: 2}
+		assert.NoError(t, game.Try("SCORE"))
+		assert.NoError(t, InDir(dir).SaveGame(game))
+
+		s, err := SQLite(dir)
+		require.NoError(t, err)
+		defer s.Close()
+
+		got, err := s.LoadGame()
+		require.NoError(t, err)
+		assert.Equal(t, 2, got.PuzzleNumber)
+		history, err := s.History()
+		assert.NoError(t, err)
+		assert.Len(t, history, 1)
+	})
+
+	t.Run("a tampered game returns an error", func(t *testing.T) {
+		s, err := SQLite(t.TempDir())
+		require.NoError(t, err)
+		defer s.Close()
+		game := &wordle.Status{Wordle: "CHAIR", PuzzleNumber: 1}
+		assert.NoError(t, game.Try("SCORE"))
+		assert.NoError(t, s.SaveGame(game))
+
+		_, err = s.(*sqliteStore).db.Exec(`UPDATE game SET status = replace(status, '"round":1', '"round":2')`)
+		require.NoError(t, err)
+		_, err = s.LoadGame()
+		assert.ErrorIs(t, err, wordle.ErrTampered)
+	})
+}
@@ -0,0 +1,220 @@
+package terminal
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Alvaroalonsobabbel/wordle/leaderboard"
+	"github.com/Alvaroalonsobabbel/wordle/wordle"
+)
+
+const (
+	accessibleIntro = "Wordle, 6 attempts to find a 5-letter word. " + accessibleHelp
+	accessibleHelp  = "Type a word and press Enter to guess it. " +
+		"Type keyboard to hear the letters tried so far, board to hear your guesses, help to hear this again or quit to exit."
+	accessiblePostGame     = "Type share to copy your result, " + accessibleShare + " or quit to exit."
+	accessiblePostGamePost = "Type share to copy your result, " + accessibleShare + ", post to post it to the leaderboard or quit to exit."
+	accessibleShare        = "share spoiler to copy it with your guesses hidden, share ascii to copy it without emoji, share image to save a picture of it"
+)
+
+var resultNames = map[int]string{
+	wordle.Correct: "correct",
+	wordle.Present: "present",
+	wordle.Absent:  "absent",
+}
+
+// WithAccessible plays the game one line at a time with plain text
+// meant to be read by screen readers: no cursor movement, colors nor
+// animations. Guesses are typed as a word followed by Enter.
+func WithAccessible() ConfigSetter {
+	return func(t *terminal) {
+		t.accessible = true
+	}
+}
+
+// startAccessible plays the game in the terminal's cooked mode, which
+// screen readers and line editing already know how to handle.
+func (t *terminal) startAccessible(shutdown <-chan os.Signal) error {
+	done := make(chan struct{})
+	defer close(done)
+	lines := readLines(t.reader, done)
+	read := func() (string, bool) {
+		select {
+		case line, ok := <-lines:
+			return strings.ToLower(strings.TrimSpace(line)), ok
+		case <-shutdown:
+			return "", false
+		}
+	}
+
+	t.announce(accessibleIntro)
+	if len(t.wordle.Results) > 0 {
+		t.announceBoard()
+	}
+
+	for !t.wordle.Finish() {
+		t.announce(fmt.Sprintf("Guess %d of 6:", t.wordle.Round+1))
+		line, ok := read()
+		if !ok {
+			return t.save()
+		}
+
+		switch line {
+		case "":
+		case "quit", "exit":
+			return t.save()
+		case "help":
+			t.announce(accessibleHelp)
+		case "board":
+			t.announceBoard()
+		case "keyboard":
+			t.announceKeyboard()
+		default:
+			t.guess(strings.ToUpper(line))
+		}
+	}
+
+	t.announce(t.finishingMsg() + ".")
+	if t.poster != nil {
+		t.announce(accessiblePostGamePost)
+	} else {
+		t.announce(accessiblePostGame)
+	}
+	for {
+		line, ok := read()
+		if !ok {
+			return t.save()
+		}
+
+		switch line {
+		case "share", "share nyt", "share spoiler", "share ascii":
+			format := wordle.ShareFormat(strings.TrimPrefix(strings.TrimPrefix(line, "share"), " "))
+			if err := t.copy(t.wordle.ShareAs(format)); err != nil {
+				t.announce("Unable to copy to Clipboard.")
+				break
+			}
+			t.announce("Copied to Clipboard.")
+		case "share image":
+			t.announce(t.shareImage() + ".")
+		case "post":
+			if t.poster != nil {
+				t.announcePost()
+			}
+		case "board":
+			t.announceBoard()
+		case "quit", "exit":
+			return t.save()
+		}
+	}
+}
+
+// readLines sends every line read from r until it's closed or done is.
+func readLines(r io.Reader, done <-chan struct{}) <-chan string {
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		s := bufio.NewScanner(r)
+		for s.Scan() {
+			select {
+			case lines <- s.Text():
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return lines
+}
+
+func (t *terminal) guess(word string) {
+	if len([]rune(word)) != 5 {
+		t.announce("Not enough letters, guesses have 5 letters.")
+		return
+	}
+	if err := t.wordle.Try(word); err != nil {
+		t.announce(err.Error() + ".")
+		return
+	}
+
+	t.announce(describeResult(t.wordle.Results[len(t.wordle.Results)-1]) + ".")
+	if err := t.save(); err != nil {
+		t.announce("Unable to save the game.")
+	}
+}
+
+func (t *terminal) announcePost() {
+	err := t.poster.Post(t.wordle)
+	switch {
+	case err == nil:
+		t.announce("Posted to the leaderboard.")
+	case errors.Is(err, leaderboard.ErrQueued):
+		t.announce("Offline, will post later.")
+	default:
+		t.announce(err.Error() + ".")
+	}
+}
+
+func (t *terminal) announceBoard() {
+	if len(t.wordle.Results) == 0 {
+		t.announce("No guesses yet.")
+		return
+	}
+
+	for i, res := range t.wordle.Results {
+		t.announce(fmt.Sprintf("Guess %d: %s.", i+1, describeResult(res)))
+	}
+}
+
+// announceKeyboard reads the letters grouped the same way the keyboard colors them.
+func (t *terminal) announceKeyboard() {
+	groups := []struct {
+		name    string
+		style   style
+		letters []string
+	}{
+		{name: "Correct", style: styleCorrect},
+		{name: "Present", style: stylePresent},
+		{name: "Absent", style: styleAbsent},
+		{name: "Not tried", style: styleDefault},
+	}
+	for _, row := range keyboardLayout {
+		for _, k := range row {
+			if k == enterKey || k == backspaceKey {
+				continue
+			}
+			for i := range groups {
+				if t.keyboard.keyStyle(k) == groups[i].style {
+					groups[i].letters = append(groups[i].letters, k)
+				}
+			}
+		}
+	}
+
+	var parts []string
+	for _, g := range groups {
+		if len(g.letters) > 0 {
+			parts = append(parts, g.name+": "+strings.Join(g.letters, " "))
+		}
+	}
+	t.announce(strings.Join(parts, ". ") + ".")
+}
+
+// describeResult reads a guess as its letters followed by their result, i.e. "A correct, R absent".
+func describeResult(res []map[rune]int) string {
+	var letters []string
+	for _, stat := range res {
+		for k, v := range stat {
+			letters = append(letters, fmt.Sprintf("%c %s", k, resultNames[v]))
+		}
+	}
+
+	return strings.Join(letters, ", ")
+}
+
+func (t *terminal) announce(s string) {
+	fmt.Fprintln(t.writer, s)
+}
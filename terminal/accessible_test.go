@@ -0,0 +1,84 @@
+package terminal
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Alvaroalonsobabbel/wordle/wordle"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessible(t *testing.T) {
+	play := func(w *wordle.Status, input string, conf ...ConfigSetter) (string, *mockSaver) {
+		t.Helper()
+		buf, saver := &bytes.Buffer{}, &mockSaver{}
+		conf = append([]ConfigSetter{
+			WithInput(strings.NewReader(input)),
+			WithOutput(buf),
+			WithTTY(&mockTTY{}),
+			WithSaver(saver),
+			WithAccessible(),
+		}, conf...)
+		assert.NoError(t, New(w, conf...).Start())
+
+		return buf.String(), saver
+	}
+
+	t.Run("announces every guess in words", func(t *testing.T) {
+		out, _ := play(&wordle.Status{Wordle: "HELLO"}, "abc\nxxxxx\nollie\nquit\n")
+
+		assert.Contains(t, out, "Guess 1 of 6:\nNot enough letters, guesses have 5 letters.\n")
+		assert.Contains(t, out, "Not in word list: XXXXX.\n")
+		assert.Contains(t, out, "O present, L present, L correct, I absent, E present.\nGuess 2 of 6:\n")
+		assert.NotContains(t, out, "\x1b")
+	})
+
+	t.Run("keyboard and board on demand", func(t *testing.T) {
+		out, _ := play(&wordle.Status{Wordle: "HELLO"}, "board\nollie\nkeyboard\nboard\n")
+
+		assert.Contains(t, out, "No guesses yet.\n")
+		assert.Contains(t, out, "Correct: L. Present: E O. Absent: I. Not tried: Q W R T Z U P A S D F G H J K Y X C V B N M.\n")
+		assert.Contains(t, out, "Guess 1: O present, L present, L correct, I absent, E present.\n")
+	})
+
+	t.Run("the tty is not set to raw mode", func(t *testing.T) {
+		tty := &mockTTY{}
+		assert.NoError(t, New(&wordle.Status{Wordle: "HELLO"}, WithInput(strings.NewReader("")), WithOutput(&bytes.Buffer{}), WithTTY(tty), WithSaver(nil), WithAccessible()).Start())
+		assert.False(t, tty.raw)
+	})
+
+	t.Run("finishing and sharing the game", func(t *testing.T) {
+		var copied string
+		w := &wordle.Status{Wordle: "HELLO"}
+		out, saver := play(w, "cello\nhello\nshare\npost\nquit\n",
+			func(t *terminal) { t.copy = func(s string) error { copied = s; return nil } },
+			WithPoster(&mockPoster{}),
+		)
+
+		assert.Contains(t, out, "H correct, E correct, L correct, L correct, O correct.\nMagnificent.\n"+accessiblePostGamePost+"\n")
+		assert.Contains(t, out, "Copied to Clipboard.\nPosted to the leaderboard.\n")
+		assert.Equal(t, 3, saver.saves, "saved after every guess and when quitting")
+		assert.Equal(t, w.Share(), copied)
+		assert.Equal(t, w, saver.saved)
+	})
+
+	t.Run("sharing in other formats", func(t *testing.T) {
+		var copied []string
+		var image string
+		w := &wordle.Status{Wordle: "HELLO", PuzzleNumber: 7}
+		out, _ := play(w, "hello\nshare spoiler\nshare ascii\nshare image\nquit\n",
+			func(t *terminal) { t.copy = func(s string) error { copied = append(copied, s); return nil } },
+			WithImageWriter(func(name string, _ []byte) error { image = name; return nil }),
+		)
+
+		assert.Equal(t, []string{w.ShareAs(wordle.ShareSpoiler), w.ShareAs(wordle.ShareASCII)}, copied)
+		assert.Equal(t, "wordle-7.png", image)
+		assert.Contains(t, out, "Copied to Clipboard.\nCopied to Clipboard.\nSaved wordle-7.png.\n")
+	})
+
+	t.Run("a game in progress is saved when the input ends", func(t *testing.T) {
+		_, saver := play(&wordle.Status{Wordle: "HELLO"}, "chair\n")
+		assert.Equal(t, 1, saver.saved.Round)
+	})
+}
@@ -0,0 +1,65 @@
+package terminal
+
+import "time"
+
+const (
+	revealDuration = 250 * time.Millisecond
+	shakeStep      = 50 * time.Millisecond
+	flashDuration  = 25 * time.Millisecond
+	bounceStep     = 100 * time.Millisecond
+)
+
+// Motion is how much the game moves.
+type Motion string
+
+const (
+	// MotionFull plays every animation.
+	MotionFull Motion = "full"
+	// MotionReduced keeps the key flashes but nothing moves: rows don't
+	// shake nor bounce and results are revealed at once.
+	MotionReduced Motion = "reduced"
+	// MotionOff disables every animation.
+	MotionOff Motion = "off"
+)
+
+// Animation holds the animation settings.
+type Animation struct {
+	// Speed multiplies how fast animations play, 2 plays them twice as
+	// fast. Anything but a positive number plays them at normal speed.
+	Speed  float64
+	Motion Motion
+}
+
+// WithAnimation changes how the game is animated.
+func WithAnimation(a Animation) ConfigSetter {
+	return func(t *terminal) {
+		t.animation = a
+	}
+}
+
+func (a Animation) scale(d time.Duration) time.Duration {
+	if a.Speed <= 0 {
+		return d
+	}
+
+	return time.Duration(float64(d) / a.Speed)
+}
+
+// moves reports whether tiles can move around the screen.
+func (a Animation) moves() bool {
+	return a.Motion == MotionFull || a.Motion == ""
+}
+
+// flashes reports whether keys can flash when pressed.
+func (a Animation) flashes() bool {
+	return a.Motion != MotionOff
+}
+
+// clock is what animations and errors wait on, tests replace it so they don't sleep.
+type clock interface {
+	AfterFunc(time.Duration, func())
+}
+
+type realClock struct{}
+
+func (realClock) AfterFunc(d time.Duration, f func()) { time.AfterFunc(d, f) }
@@ -0,0 +1,99 @@
+package terminal
+
+import (
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Alvaroalonsobabbel/wordle/wordle"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClock doesn't wait, it records what the game waited for.
+type fakeClock struct {
+	mu    sync.Mutex
+	after []time.Duration
+}
+
+func (c *fakeClock) AfterFunc(d time.Duration, f func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.after = append(c.after, d)
+	go f()
+}
+
+func (c *fakeClock) waits() []time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return slices.Clone(c.after)
+}
+
+func TestAnimation(t *testing.T) {
+	newTerminal := func(a Animation) (*terminal, *fakeClock) {
+		terminal := newTestTerminal(io.Discard, strings.NewReader(""))
+		terminal.render.anim = a
+		return terminal, terminal.loop.clock.(*fakeClock)
+	}
+	repeat := func(d time.Duration, n int) []time.Duration {
+		return slices.Repeat([]time.Duration{d}, n)
+	}
+
+	t.Run("results flip one letter at a time", func(t *testing.T) {
+		terminal, clock := newTerminal(Animation{})
+		assert.NoError(t, terminal.wordle.Try("CHAIR"))
+		var done bool
+		terminal.round.renderResult(func() { done = true })
+		terminal.loop.settle()
+
+		assert.True(t, done)
+		assert.Equal(t, repeat(revealDuration/2, 10), clock.waits())
+	})
+
+	t.Run("winning bounces the row", func(t *testing.T) {
+		terminal, clock := newTerminal(Animation{Motion: MotionFull})
+		assert.NoError(t, terminal.wordle.Try("CHORE"))
+		terminal.round.renderResult(func() {})
+		terminal.loop.settle()
+
+		assert.Equal(t, append(repeat(revealDuration/2, 10), repeat(bounceStep, 5)...), clock.waits())
+		assert.Equal(t, -1, terminal.round.bounce)
+	})
+
+	t.Run("speed scales every animation", func(t *testing.T) {
+		terminal, clock := newTerminal(Animation{Speed: 2})
+		terminal.typeKeys("a\r")
+		terminal.loop.settle()
+
+		after := clock.waits()
+		assert.Len(t, slices.DeleteFunc(slices.Clone(after), func(d time.Duration) bool { return d != shakeStep/2 }), 6)
+		assert.Contains(t, after, flashDuration/2)
+		assert.Contains(t, after, errDuration, "errors are not animations")
+	})
+
+	t.Run("reduced motion only flashes the keys", func(t *testing.T) {
+		terminal, clock := newTerminal(Animation{Motion: MotionReduced})
+		terminal.typeKeys("CHORE\r")
+		terminal.loop.settle()
+
+		assert.Equal(t, repeat(flashDuration, 6), clock.waits())
+	})
+
+	t.Run("no animations", func(t *testing.T) {
+		terminal, clock := newTerminal(Animation{Motion: MotionOff})
+		terminal.typeKeys("a\r")
+		terminal.loop.settle()
+
+		assert.Equal(t, []time.Duration{errDuration}, clock.waits())
+		assert.Equal(t, 0, terminal.round.offset)
+	})
+}
+
+func TestWithAnimation(t *testing.T) {
+	terminal := New(&wordle.Status{Wordle: "HELLO"}, WithOutput(io.Discard), WithTTY(nil), WithAnimation(Animation{Speed: 3, Motion: MotionReduced}))
+	assert.Equal(t, Animation{Speed: 3, Motion: MotionReduced}, terminal.render.anim)
+}
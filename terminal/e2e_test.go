@@ -0,0 +1,128 @@
+package terminal
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Alvaroalonsobabbel/wordle/race"
+	"github.com/Alvaroalonsobabbel/wordle/wordle"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEndToEnd(t *testing.T) {
+	t.Run("initial screen", func(t *testing.T) {
+		h := newHarness(t, &wordle.Status{Wordle: "HELLO"})
+		s := h.waitFor("6 attempts to find a 5-letter word")
+
+		assert.Equal(t, "                                 _  _  _  _  _", s.line(3))
+		assert.Equal(t, "                                 _  _  _  _  _", s.line(8))
+		assert.Equal(t, "                          Q  W  E  R  T  Z  U  I  O  P", s.line(11))
+		assert.Equal(t, "                           A  S  D  F  G  H  J  K  L", s.line(12))
+		assert.Equal(t, "                          ↩  Y  X  C  V  B  N  M  ←", s.line(13))
+
+		h.press("\x03")
+		assert.NoError(t, h.wait())
+	})
+
+	t.Run("typing and deleting letters", func(t *testing.T) {
+		h := newHarness(t, &wordle.Status{Wordle: "HELLO"})
+		h.press("cha")
+		h.waitFor("                                 C  H  A  _  _")
+
+		h.press("\x7f")
+		h.waitFor("                                 C  H  _  _  _")
+
+		h.press("\x03")
+		assert.NoError(t, h.wait())
+	})
+
+	t.Run("errors are shown next to the board", func(t *testing.T) {
+		h := newHarness(t, &wordle.Status{Wordle: "HELLO"})
+		h.press("abc\r")
+		assert.Contains(t, h.waitFor("Not enough letters").line(3), "Not enough letters")
+
+		h.press("de\r")
+		h.waitFor("Not in word list: ABCDE")
+
+		h.press("\x03")
+		assert.NoError(t, h.wait())
+	})
+
+	t.Run("winning the game", func(t *testing.T) {
+		game := &wordle.Status{Wordle: "HELLO"}
+		h := newHarness(t, game)
+		h.press("CELLO\r")
+		h.press("HELLO\r")
+
+		s := h.waitFor("(s)hare (e)xit")
+		assert.Equal(t, "                                 C  E  L  L  O", s.line(3))
+		assert.Equal(t, "                                 H  E  L  L  O", s.line(4))
+		assert.Equal(t, "Magnificent", strings.TrimSpace(s.line(10)))
+		assert.Equal(t, "(s)hare (e)xit", strings.TrimSpace(s.line(15)))
+
+		h.press("e")
+		assert.NoError(t, h.wait())
+		assert.Equal(t, game, h.saver.saved)
+		assert.True(t, game.Finish())
+	})
+
+	t.Run("quitting saves the game in progress", func(t *testing.T) {
+		game := &wordle.Status{Wordle: "HELLO"}
+		h := newHarness(t, game)
+		h.press("CHAIR\r")
+		h.waitFor("                                 C  H  A  I  R")
+
+		h.press("\x03")
+		assert.NoError(t, h.wait())
+		assert.Equal(t, 1, h.saver.saved.Round)
+	})
+	t.Run("the game is laid out again when the terminal is resized", func(t *testing.T) {
+		h := newHarness(t, &wordle.Status{Wordle: "HELLO"})
+		h.press("ab")
+		h.waitFor("6 attempts to find a 5-letter word")
+
+		h.resize(30, 12)
+		s := h.waitFor("Wordle")
+		assert.Equal(t, "          A B _ _ _", s.line(2))
+		assert.Equal(t, "     Q W E R T Z U I O P", s.line(9))
+
+		h.resize(20, 8)
+		h.waitFor("Terminal too small")
+
+		h.resize(80, 24)
+		s = h.waitFor("6 attempts to find a 5-letter word")
+		assert.Equal(t, "                                 A  B  _  _  _", s.line(3))
+
+		h.press("\x03")
+		assert.NoError(t, h.wait())
+	})
+}
+
+func TestStress(t *testing.T) {
+	t.Run("rapid input while animating, resizing and racing", func(t *testing.T) {
+		game := &wordle.Status{Wordle: "HELLO"}
+		racer := &mockRacer{updates: make(chan race.Update)}
+		h := newHarness(t, game, WithRace(racer))
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := range 50 {
+				racer.updates <- race.Update{Opponents: []race.Player{{Name: "alice", Rows: make([][]int, i%6)}}}
+				h.resize(50+i%30, 16+i%8)
+			}
+		}()
+		for range 20 {
+			h.press("abcde\r\x7f\x7fqq\rxyz\x7f\x7f\x7f\x7f\x7f")
+			h.press("CHAIR\r")
+		}
+		<-done
+		h.resize(minWidth, minRaceHeight)
+		h.waitFor("(s)hare (e)xit")
+
+		h.press("\x03")
+		assert.NoError(t, h.wait())
+		assert.Equal(t, game, h.saver.saved)
+		assert.True(t, game.Finish())
+	})
+}
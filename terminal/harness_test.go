@@ -0,0 +1,214 @@
+package terminal
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Alvaroalonsobabbel/wordle/wordle"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	screenWidth  = 80
+	screenHeight = 24
+	waitTimeout  = 5 * time.Second
+)
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// vt is a minimal terminal emulator that understands the escape
+// sequences the game uses, so tests can assert on what the player sees.
+type vt struct {
+	cells    [screenHeight][screenWidth]rune
+	row, col int
+}
+
+func newVT(output string) *vt {
+	v := &vt{}
+	v.clear()
+
+	r := []rune(output)
+	for i := 0; i < len(r); i++ {
+		switch {
+		case r[i] == '\033' && i+1 < len(r) && r[i+1] == '[':
+			j := i + 2
+			for j < len(r) && (r[j] < '@' || r[j] > '~') {
+				j++
+			}
+			if j < len(r) {
+				v.csi(string(r[i+2:j]), r[j])
+			}
+			i = j
+		case r[i] == '\033' && i+1 < len(r) && r[i+1] == ']':
+			for i < len(r) && r[i] != '\a' {
+				i++
+			}
+		case r[i] == '\n':
+			v.row = min(v.row+1, screenHeight-1)
+		case r[i] == '\r':
+			v.col = 0
+		case r[i] == '\ufe0e' || r[i] == '\ufe0f':
+			// Variation selectors take no space.
+		default:
+			if v.col < screenWidth {
+				v.cells[v.row][v.col] = r[i]
+			}
+			v.col++
+		}
+	}
+
+	return v
+}
+
+func (v *vt) csi(params string, final rune) {
+	var args []int
+	for _, p := range strings.Split(strings.TrimPrefix(params, "?"), ";") {
+		n, _ := strconv.Atoi(p)
+		args = append(args, n)
+	}
+	arg := func(i int) int {
+		if i < len(args) && args[i] > 0 {
+			return args[i]
+		}
+		return 1
+	}
+
+	switch final {
+	case 'H', 'f':
+		v.row = min(arg(0), screenHeight) - 1
+		v.col = min(arg(1), screenWidth) - 1
+	case 'J':
+		if params == "2" {
+			v.clear()
+		}
+	case 'K':
+		for c := v.col; c < screenWidth; c++ {
+			v.cells[v.row][c] = ' '
+		}
+	}
+}
+
+func (v *vt) clear() {
+	for r := range v.cells {
+		for c := range v.cells[r] {
+			v.cells[r][c] = ' '
+		}
+	}
+}
+
+// line returns the text of a 1-based row without trailing spaces.
+func (v *vt) line(row int) string {
+	return strings.TrimRight(string(v.cells[row-1][:]), " ")
+}
+
+func (v *vt) String() string {
+	var lines []string
+	for r := range v.cells {
+		lines = append(lines, v.line(r+1))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// harness plays a game end to end, feeding key presses to the
+// terminal and reading back the screen it renders.
+type harness struct {
+	t     *testing.T
+	in    *io.PipeWriter
+	out   *syncBuffer
+	saver *mockSaver
+	tty   *mockTTY
+	done  chan error
+}
+
+func newHarness(t *testing.T, w *wordle.Status, conf ...ConfigSetter) *harness {
+	t.Helper()
+	r, in := io.Pipe()
+	h := &harness{
+		t:     t,
+		in:    in,
+		out:   &syncBuffer{},
+		saver: &mockSaver{},
+		tty:   &mockTTY{width: screenWidth, height: screenHeight, resized: make(chan struct{})},
+		done:  make(chan error, 1),
+	}
+
+	conf = append([]ConfigSetter{
+		WithInput(r),
+		WithOutput(h.out),
+		WithTTY(h.tty),
+		WithSaver(h.saver),
+		// Fast enough not to slow down the tests, errors still stay on the screen.
+		WithAnimation(Animation{Speed: 50}),
+	}, conf...)
+	term := New(w, conf...)
+
+	go func() { h.done <- term.Start() }()
+	t.Cleanup(func() { in.Close() })
+
+	return h
+}
+
+func (h *harness) press(keys string) {
+	h.t.Helper()
+	_, err := io.WriteString(h.in, keys)
+	require.NoError(h.t, err)
+}
+
+// resize changes the size of the terminal, the screen the harness reads
+// back stays the same size.
+func (h *harness) resize(width, height int) {
+	h.tty.setSize(width, height)
+	h.tty.resized <- struct{}{}
+}
+
+func (h *harness) screen() *vt {
+	return newVT(h.out.String())
+}
+
+// waitFor waits until the screen shows text and returns it.
+func (h *harness) waitFor(text string) *vt {
+	h.t.Helper()
+	deadline := time.Now().Add(waitTimeout)
+	for {
+		s := h.screen()
+		if strings.Contains(s.String(), text) {
+			return s
+		}
+		if time.Now().After(deadline) {
+			require.FailNowf(h.t, "text not found on screen", "%q not in:\n%s", text, s)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// wait waits for the game to exit.
+func (h *harness) wait() error {
+	h.t.Helper()
+	select {
+	case err := <-h.done:
+		return err
+	case <-time.After(waitTimeout):
+		require.FailNow(h.t, "game didn't exit")
+		return nil
+	}
+}
@@ -9,41 +9,41 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func TestPrintKey(t *testing.T) {
+func TestKeyStyle(t *testing.T) {
 	tests := []struct {
 		initialWord string
 		tries       []string
-		want        map[string]string
+		want        map[string]style
 	}{
 		{
 			initialWord: "ENDOW",
 			tries:       []string{"STING", "KNEEL"},
-			want: map[string]string{
-				"E": "\x1b[11;8H\x1b[7m\x1b[33m E \x1b[0m",
-				"T": "\x1b[11;14H\x1b[7m\x1b[90m T \x1b[0m",
-				"I": "\x1b[11;23H\x1b[7m\x1b[90m I \x1b[0m",
-				"S": "\x1b[12;6H\x1b[7m\x1b[90m S \x1b[0m",
-				"G": "\x1b[12;15H\x1b[7m\x1b[90m G \x1b[0m",
-				"K": "\x1b[12;24H\x1b[7m\x1b[90m K \x1b[0m",
-				"L": "\x1b[12;27H\x1b[7m\x1b[90m L \x1b[0m",
-				"N": "\x1b[13;20H\x1b[7m\x1b[32m N \x1b[0m",
-				"Z": "\x1b[11;17H Z ",
+			want: map[string]style{
+				"E": stylePresent,
+				"T": styleAbsent,
+				"I": styleAbsent,
+				"S": styleAbsent,
+				"G": styleAbsent,
+				"K": styleAbsent,
+				"L": styleAbsent,
+				"N": styleCorrect,
+				"Z": styleDefault,
 			},
 		},
 		{
 			initialWord: "HEFTY",
 			tries:       []string{"REACT", "DETOX", "TENET"},
-			want: map[string]string{
-				"E": "\x1b[11;8H\x1b[7m\x1b[32m E \x1b[0m",
-				"R": "\x1b[11;11H\x1b[7m\x1b[90m R \x1b[0m",
-				"T": "\x1b[11;14H\x1b[7m\x1b[33m T \x1b[0m",
-				"O": "\x1b[11;26H\x1b[7m\x1b[90m O \x1b[0m",
-				"A": "\x1b[12;3H\x1b[7m\x1b[90m A \x1b[0m",
-				"D": "\x1b[12;9H\x1b[7m\x1b[90m D \x1b[0m",
-				"X": "\x1b[13;8H\x1b[7m\x1b[90m X \x1b[0m",
-				"C": "\x1b[13;11H\x1b[7m\x1b[90m C \x1b[0m",
-				"N": "\x1b[13;20H\x1b[7m\x1b[90m N \x1b[0m",
-				"M": "\x1b[13;23H M ",
+			want: map[string]style{
+				"E": styleCorrect,
+				"R": styleAbsent,
+				"T": stylePresent,
+				"O": styleAbsent,
+				"A": styleAbsent,
+				"D": styleAbsent,
+				"X": styleAbsent,
+				"C": styleAbsent,
+				"N": styleAbsent,
+				"M": styleDefault,
 			},
 		},
 	}
@@ -51,17 +51,46 @@ func TestPrintKey(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.initialWord+": "+strings.Join(test.tries, " "), func(t *testing.T) {
 			w := &wordle.Status{Wordle: test.initialWord}
-			kb := newKeyboard(w, newRender(io.Discard))
+			kb := newKeyboard(w, newRender(io.Discard, newLoop(&fakeClock{})))
 
 			for _, word := range test.tries {
 				assert.NoError(t, w.Try(word))
 			}
 
-			kb.print()
-
 			for char, want := range test.want {
-				assert.Equal(t, want, kb.keys[char].string())
+				assert.Equal(t, want, kb.keyStyle(char), char)
 			}
 		})
 	}
 }
+
+func TestKeyboardDraw(t *testing.T) {
+	w := &wordle.Status{Wordle: "ENDOW"}
+	kb := newKeyboard(w, newRender(io.Discard, newLoop(&fakeClock{})))
+	assert.NoError(t, w.Try("STING"))
+	kb.flashed = "Q"
+
+	t.Run("regular layout", func(t *testing.T) {
+		s, l := newScreen(50, 16), newLayout(50, 16, false)
+		kb.draw(s, l)
+
+		assert.Equal(t, "           Q  W  E  R  T  Z  U  I  O  P", s.text(l.keyboardRow))
+		assert.Equal(t, "            A  S  D  F  G  H  J  K  L", s.text(l.keyboardRow+1))
+		assert.Equal(t, "           ↩︎  Y  X  C  V  B  N  M  ←", s.text(l.keyboardRow+2))
+		assert.Equal(t, styleFlash, s.style(l.keyboardRow, 11))
+		assert.Equal(t, styleAbsent, s.style(l.keyboardRow+1, 15))
+		assert.Equal(t, stylePresent, s.style(l.keyboardRow+2, 29))
+	})
+
+	t.Run("compact layout", func(t *testing.T) {
+		s, l := newScreen(30, 12), newLayout(30, 12, false)
+		kb.draw(s, l)
+
+		assert.Equal(t, "     Q W E R T Z U I O P", s.text(l.keyboardRow))
+		assert.Equal(t, "      A S D F G H J K L", s.text(l.keyboardRow+1))
+		assert.Equal(t, "     ↩︎ Y X C V B N M ←", s.text(l.keyboardRow+2))
+		assert.Equal(t, styleFlash, s.style(l.keyboardRow, 5))
+		assert.Equal(t, styleAbsent, s.style(l.keyboardRow+1, 8))
+		assert.Equal(t, stylePresent, s.style(l.keyboardRow+2, 17))
+	})
+}
@@ -0,0 +1,215 @@
+package terminal
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// Control characters.
+	ctrlA = 0x01
+	ctrlB = 0x02
+	ctrlC = 0x03
+	ctrlE = 0x05
+	ctrlF = 0x06
+	ctrlH = 0x08
+	ctrlU = 0x15
+	ctrlW = 0x17
+	esc   = 0x1b
+	// backspace is what terminals send for the backspace key, ctrl-H
+	// is sent by some of them instead.
+	backspace = 0x7f
+
+	enablePaste  = "\033[?2004h"
+	disablePaste = "\033[?2004l"
+	// Clicks are reported with SGR coordinates, which work on wide terminals.
+	enableMouse  = "\033[?1000h\033[?1006h"
+	disableMouse = "\033[?1006l\033[?1000l"
+)
+
+var pasteEnd = []byte("\033[201~")
+
+type keyKind int
+
+const (
+	keyRune keyKind = iota
+	keyEnter
+	keyBackspace
+	keyDelete
+	keyLeft
+	keyRight
+	keyHome
+	keyEnd
+	keyCtrlC
+	keyCtrlU
+	keyCtrlW
+	keyPaste
+	keyEscape
+	keyClick
+	keyUnknown
+)
+
+// keyEvent is a key press or a paste decoded from the input.
+type keyEvent struct {
+	kind keyKind
+	// r is the character typed for keyRune.
+	r rune
+	// text is what was pasted for keyPaste.
+	text string
+	// row and col are the cell clicked for keyClick, from 0.
+	row, col int
+}
+
+func runeKey(r rune) keyEvent { return keyEvent{kind: keyRune, r: r} }
+
+// decoder turns the bytes read from a terminal in raw mode into key
+// events. Sequences split across reads are kept until they're complete.
+type decoder struct {
+	buf     []byte
+	pasting bool
+}
+
+// feed decodes b, returning the events of every complete key.
+func (d *decoder) feed(b []byte) []keyEvent {
+	d.buf = append(d.buf, b...)
+
+	var events []keyEvent
+	for len(d.buf) > 0 {
+		e, n := d.next()
+		if n == 0 {
+			break
+		}
+		d.buf = d.buf[n:]
+		if e.kind != keyUnknown {
+			events = append(events, e)
+		}
+	}
+	// A lone escape at the end of a read is the escape key, terminals
+	// send whole sequences in a single write.
+	if len(d.buf) == 1 && d.buf[0] == esc && !d.pasting {
+		d.buf = d.buf[:0]
+		events = append(events, keyEvent{kind: keyEscape})
+	}
+
+	return events
+}
+
+// next decodes the key at the start of the buffer and how many bytes it
+// takes, 0 when it's not complete yet.
+func (d *decoder) next() (keyEvent, int) {
+	if d.pasting {
+		i := bytes.Index(d.buf, pasteEnd)
+		if i < 0 {
+			return keyEvent{}, 0
+		}
+		d.pasting = false
+		return keyEvent{kind: keyPaste, text: string(d.buf[:i])}, i + len(pasteEnd)
+	}
+
+	switch b := d.buf[0]; b {
+	case esc:
+		return d.escape()
+	case '\r', '\n':
+		return keyEvent{kind: keyEnter}, 1
+	case backspace, ctrlH:
+		return keyEvent{kind: keyBackspace}, 1
+	case ctrlC:
+		return keyEvent{kind: keyCtrlC}, 1
+	case ctrlU:
+		return keyEvent{kind: keyCtrlU}, 1
+	case ctrlW:
+		return keyEvent{kind: keyCtrlW}, 1
+	case ctrlA:
+		return keyEvent{kind: keyHome}, 1
+	case ctrlE:
+		return keyEvent{kind: keyEnd}, 1
+	case ctrlB:
+		return keyEvent{kind: keyLeft}, 1
+	case ctrlF:
+		return keyEvent{kind: keyRight}, 1
+	default:
+		if b < 0x20 {
+			return keyEvent{kind: keyUnknown}, 1
+		}
+		if !utf8.FullRune(d.buf) {
+			return keyEvent{}, 0
+		}
+		r, n := utf8.DecodeRune(d.buf)
+		return runeKey(r), n
+	}
+}
+
+// escape decodes CSI (ESC [) and SS3 (ESC O) sequences.
+func (d *decoder) escape() (keyEvent, int) {
+	if len(d.buf) < 2 {
+		return keyEvent{}, 0
+	}
+
+	switch d.buf[1] {
+	case '[':
+		// Parameter bytes until the final byte, see ECMA-48 5.4.
+		for i := 2; i < len(d.buf); i++ {
+			if c := d.buf[i]; c >= 0x40 && c <= 0x7e {
+				return d.csi(string(d.buf[2:i]), c), i + 1
+			}
+		}
+		return keyEvent{}, 0
+	case 'O':
+		if len(d.buf) < 3 {
+			return keyEvent{}, 0
+		}
+		return d.csi("", d.buf[2]), 3
+	default:
+		// Alt combinations are not used, the escape is dropped.
+		return keyEvent{kind: keyEscape}, 1
+	}
+}
+
+func (d *decoder) csi(params string, final byte) keyEvent {
+	switch final {
+	case 'C':
+		return keyEvent{kind: keyRight}
+	case 'D':
+		return keyEvent{kind: keyLeft}
+	case 'H':
+		return keyEvent{kind: keyHome}
+	case 'F':
+		return keyEvent{kind: keyEnd}
+	case 'M':
+		return click(params)
+	case '~':
+		n, err := strconv.Atoi(params)
+		if err != nil {
+			break
+		}
+		switch n {
+		case 1, 7:
+			return keyEvent{kind: keyHome}
+		case 4, 8:
+			return keyEvent{kind: keyEnd}
+		case 3:
+			return keyEvent{kind: keyDelete}
+		case 200:
+			d.pasting = true
+		}
+	}
+
+	return keyEvent{kind: keyUnknown}
+}
+
+// click decodes the press of the left button, reported as "<0;col;row".
+func click(params string) keyEvent {
+	p, ok := strings.CutPrefix(params, "<")
+	if !ok {
+		return keyEvent{kind: keyUnknown}
+	}
+	var b, col, row int
+	if n, err := fmt.Sscanf(p, "%d;%d;%d", &b, &col, &row); err != nil || n != 3 || b != 0 {
+		return keyEvent{kind: keyUnknown}
+	}
+
+	return keyEvent{kind: keyClick, row: row - 1, col: col - 1}
+}
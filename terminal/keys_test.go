@@ -0,0 +1,88 @@
+package terminal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecoder(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []string
+		want  []keyEvent
+	}{
+		{
+			name:  "letters and control keys",
+			input: []string{"ab\r\x7f\x08\x03\x15\x17"},
+			want: []keyEvent{
+				runeKey('a'), runeKey('b'), {kind: keyEnter}, {kind: keyBackspace},
+				{kind: keyBackspace}, {kind: keyCtrlC}, {kind: keyCtrlU}, {kind: keyCtrlW},
+			},
+		},
+		{
+			name:  "arrows, home, end and delete",
+			input: []string{"\x1b[D\x1b[C\x1b[H\x1b[F\x1b[1~\x1b[4~\x1b[3~\x1bOD\x1bOC"},
+			want: []keyEvent{
+				{kind: keyLeft}, {kind: keyRight}, {kind: keyHome}, {kind: keyEnd},
+				{kind: keyHome}, {kind: keyEnd}, {kind: keyDelete}, {kind: keyLeft}, {kind: keyRight},
+			},
+		},
+		{
+			name:  "emacs movement keys",
+			input: []string{"\x01\x05\x02\x06"},
+			want:  []keyEvent{{kind: keyHome}, {kind: keyEnd}, {kind: keyLeft}, {kind: keyRight}},
+		},
+		{
+			name:  "unknown sequences and control characters are dropped",
+			input: []string{"\x1b[A\x1b[1;5B\x1b[<0;10;5m\x1b[<2;10;5M\x00a"},
+			want:  []keyEvent{runeKey('a')},
+		},
+		{
+			name:  "sequences split across reads",
+			input: []string{"\x1b[", "3", "~", "\x1bO", "D"},
+			want:  []keyEvent{{kind: keyDelete}, {kind: keyLeft}},
+		},
+		{
+			name:  "lone escape",
+			input: []string{"\x1b", "a"},
+			want:  []keyEvent{{kind: keyEscape}, runeKey('a')},
+		},
+		{
+			name:  "bracketed paste",
+			input: []string{"\x1b[200~cra", "ne\r\x1b[201~\r"},
+			want:  []keyEvent{{kind: keyPaste, text: "crane\r"}, {kind: keyEnter}},
+		},
+		{
+			name:  "left clicks",
+			input: []string{"\x1b[<0;10;5M\x1b[<0;1;1m\x1b[<0;120;40M"},
+			want:  []keyEvent{{kind: keyClick, row: 4, col: 9}, {kind: keyClick, row: 39, col: 119}},
+		},
+		{
+			name:  "multibyte characters",
+			input: []string{"ñ\xc3", "\xa9"},
+			want:  []keyEvent{runeKey('ñ'), runeKey('é')},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				d   decoder
+				got []keyEvent
+			)
+			for _, in := range test.input {
+				got = append(got, d.feed([]byte(in))...)
+			}
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+// typeKeys handles the keys decoded from s as if they were typed.
+func (t *terminal) typeKeys(s string) {
+	var d decoder
+	for _, e := range d.feed([]byte(s)) {
+		t.key(e)
+	}
+}
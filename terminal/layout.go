@@ -0,0 +1,136 @@
+package terminal
+
+import "unicode/utf8"
+
+const (
+	title      = "6 attempts to find a 5-letter word"
+	shortTitle = "Wordle"
+	tooSmall   = "Terminal too small"
+	resizeMsg  = "Please resize"
+
+	// Minimum window size the regular layout fits in.
+	minWidth  = 50
+	minHeight = 16
+	// Minimum window size the compact layout fits in.
+	minCompactWidth  = 22
+	minCompactHeight = 12
+	// Minimum window height of each layout with the race panel below the menu.
+	minRaceHeight        = 23
+	minCompactRaceHeight = 20
+
+	// The race panel has a row for the winner, one for the names and
+	// one for each attempt.
+	raceHeight = 8
+
+	// Width of the widest row of keys with its indent.
+	keyboardWidth = 31
+	// errWidth is the room left for errors when they don't fit beside the board.
+	errWidth = 30
+)
+
+// layout is where every component of the game goes for a given screen size.
+type layout struct {
+	width, height int
+	// compact squeezes the game in narrow terminals, tiles and keys take
+	// a single character and errors are shown one at a time below the board.
+	compact bool
+	// tooSmall is set when not even the compact layout fits.
+	tooSmall bool
+	// race makes room for the race panel below the menu.
+	race bool
+
+	title          string
+	titleRow       int
+	boardRow       int
+	boardColumn    int
+	tileWidth      int
+	tilePad        string
+	errRow         int
+	errColumn      int
+	errLines       int
+	footerRow      int
+	keyboardRow    int
+	keyboardColumn int
+	keyboardIndent []int
+	menuRow        int
+	raceRow        int
+	raceColumn     int
+	// marks surround the letter of tiles by style, when there's room for them.
+	marks map[style]string
+}
+
+func newLayout(width, height int, race bool) layout {
+	l := layout{width: width, height: height, race: race}
+
+	fullHeight, compactHeight := minHeight, minCompactHeight
+	if race {
+		fullHeight, compactHeight = minRaceHeight, minCompactRaceHeight
+	}
+
+	switch {
+	case width >= minWidth && height >= fullHeight:
+		l.title = title
+		l.tileWidth, l.tilePad = 3, " "
+		l.keyboardRow, l.footerRow, l.menuRow = 10, 9, 14
+		l.keyboardColumn = (width - keyboardWidth) / 2
+		l.keyboardIndent = []int{1, 2, 1}
+		// The board is centered above the keyboard.
+		l.boardRow, l.boardColumn = 2, l.keyboardColumn+8
+		l.errRow, l.errLines = 2, 6
+		l.errColumn = min(l.boardColumn+19, width-errWidth)
+		l.raceRow = 15
+	case width >= minCompactWidth && height >= compactHeight:
+		l.compact = true
+		l.title = shortTitle
+		l.tileWidth, l.tilePad = 2, ""
+		l.boardRow, l.footerRow, l.keyboardRow, l.menuRow = 1, 7, 8, 11
+		l.keyboardColumn = (width - 20) / 2
+		l.keyboardIndent = []int{0, 1, 0}
+		l.boardColumn = l.keyboardColumn + 5
+		l.errRow, l.errLines = 7, 1
+		l.raceRow = 12
+	default:
+		l.tooSmall = true
+	}
+	l.raceColumn = l.keyboardColumn
+
+	return l
+}
+
+// lastRow is the last row of the screen the game uses, the cursor is left
+// below it when the game exits.
+func (l layout) lastRow() int {
+	if l.tooSmall {
+		return max(0, l.height-1)
+	}
+
+	last := max(l.menuRow, l.raceRow)
+	if l.race {
+		last = l.raceRow + raceHeight - 1
+	}
+
+	return min(last, max(0, l.height-1))
+}
+
+// center returns the column text starts at to be centered in the screen.
+func (l layout) center(text string) int {
+	return max(0, (l.width-utf8.RuneCountInString(text))/2)
+}
+
+// tile returns how a letter of the board or keyboard is displayed.
+func (l layout) tile(letter string, st style) string {
+	if m := []rune(l.marks[st]); len(m) == 2 && l.tilePad != "" {
+		return string(m[0]) + letter + string(m[1])
+	}
+
+	return l.tilePad + letter + l.tilePad
+}
+
+// square returns how a result is displayed in the race progress.
+func (l layout) square(st style) string {
+	if m := []rune(l.marks[st]); len(m) == 2 {
+		return string(m)
+	}
+
+	return raceSquare
+}
@@ -0,0 +1,121 @@
+package terminal
+
+import (
+	"os"
+	"time"
+
+	"github.com/Alvaroalonsobabbel/wordle/race"
+)
+
+// loop runs every change to the UI state one after the other in a single
+// goroutine: key presses, animation steps, race updates and resizes.
+// Other goroutines only ever send events to it.
+type loop struct {
+	clock  clock
+	events chan func()
+	done   chan struct{}
+	// timers is how many scheduled and background events haven't run yet.
+	timers int
+}
+
+func newLoop(c clock) *loop {
+	return &loop{
+		clock:  c,
+		events: make(chan func()),
+		done:   make(chan struct{}),
+	}
+}
+
+// after schedules f to run in the loop once d has passed.
+func (l *loop) after(d time.Duration, f func()) {
+	l.timers++
+	l.clock.AfterFunc(d, func() {
+		select {
+		case l.events <- func() { l.timers--; f() }:
+		case <-l.done:
+		}
+	})
+}
+
+// background runs f in its own goroutine, so a slow call doesn't block
+// the loop, and then runs the function f returns in the loop.
+func (l *loop) background(f func() func()) {
+	l.timers++
+	go func() {
+		done := f()
+		select {
+		case l.events <- func() { l.timers--; done() }:
+		case <-l.done:
+		}
+	}()
+}
+
+// stop drops the events that haven't run yet.
+func (l *loop) stop() {
+	close(l.done)
+}
+
+// run handles the events until the player quits, the input is closed or
+// a shutdown signal is received.
+func (t *terminal) run(shutdown <-chan os.Signal) {
+	var (
+		keys    = t.readKeys()
+		updates <-chan race.Update
+		resized <-chan struct{}
+	)
+	if t.racer != nil {
+		updates = t.racer.Updates()
+	}
+	if t.tty != nil {
+		resized = t.tty.Resized()
+	}
+
+	for !t.quit {
+		select {
+		case e, ok := <-keys:
+			if !ok {
+				return
+			}
+			t.key(e)
+		case f := <-t.loop.events:
+			f()
+		case u, ok := <-updates:
+			if !ok {
+				updates = nil
+				continue
+			}
+			t.race = u
+			t.render.refresh()
+		case <-resized:
+			t.resize()
+		case <-shutdown:
+			return
+		}
+	}
+}
+
+// readKeys sends every key decoded from the input, the channel is closed
+// when the input is, i.e. an SSH session disconnecting.
+func (t *terminal) readKeys() <-chan keyEvent {
+	keys := make(chan keyEvent)
+	go func() {
+		defer close(keys)
+		var d decoder
+		buf := make([]byte, 64)
+		for {
+			n, err := t.reader.Read(buf)
+			for _, e := range d.feed(buf[:n]) {
+				select {
+				case keys <- e:
+				case <-t.loop.done:
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	return keys
+}
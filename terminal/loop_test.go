@@ -0,0 +1,79 @@
+package terminal
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Alvaroalonsobabbel/wordle/race"
+	"github.com/Alvaroalonsobabbel/wordle/wordle"
+	"github.com/stretchr/testify/assert"
+)
+
+// settle runs the scheduled events until there are none left, for tests
+// driving the game without running the loop.
+func (l *loop) settle() {
+	for l.timers > 0 {
+		(<-l.events)()
+	}
+}
+
+func TestLoop(t *testing.T) {
+	t.Run("scheduled events run in the loop", func(t *testing.T) {
+		l := newLoop(&fakeClock{})
+		var ran []int
+		l.after(time.Second, func() { ran = append(ran, 1) })
+		l.after(time.Second, func() { l.after(time.Second, func() { ran = append(ran, 2) }) })
+
+		l.settle()
+		assert.ElementsMatch(t, []int{1, 2}, ran)
+		assert.Equal(t, 0, l.timers)
+	})
+
+	t.Run("background work finishes in the loop", func(t *testing.T) {
+		l := newLoop(&fakeClock{})
+		var ran []string
+		l.background(func() func() {
+			return func() { ran = append(ran, "done") }
+		})
+
+		l.settle()
+		assert.Equal(t, []string{"done"}, ran)
+		assert.Equal(t, 0, l.timers)
+	})
+
+	t.Run("stopped loops drop the events", func(t *testing.T) {
+		l := newLoop(realClock{})
+		l.stop()
+		l.after(time.Millisecond, func() { t.Error("event ran after stopping") })
+		time.Sleep(10 * time.Millisecond)
+	})
+}
+
+func TestRun(t *testing.T) {
+	t.Run("race updates are drawn", func(t *testing.T) {
+		racer := &mockRacer{updates: make(chan race.Update)}
+		r, w := io.Pipe()
+		terminal := New(&wordle.Status{Wordle: "HELLO"}, WithInput(r), WithOutput(io.Discard), WithTTY(nil), WithSaver(nil), WithRace(racer))
+
+		done := make(chan error)
+		go func() { done <- terminal.Start() }()
+		racer.updates <- race.Update{Winner: "alice"}
+		close(racer.updates)
+		_, err := io.WriteString(w, "\x03")
+		assert.NoError(t, err)
+		assert.NoError(t, <-done)
+
+		assert.Contains(t, terminal.render.front.text(terminal.render.layout.raceRow), "alice won the race!")
+	})
+
+	t.Run("an already finished game starts in the post game menu", func(t *testing.T) {
+		game := &wordle.Status{Wordle: "HELLO"}
+		assert.NoError(t, game.Try("HELLO"))
+		terminal := New(game, WithInput(strings.NewReader("e")), WithOutput(io.Discard), WithTTY(nil), WithSaver(nil))
+
+		assert.NoError(t, terminal.Start())
+		assert.True(t, terminal.quit)
+	})
+}
@@ -0,0 +1,64 @@
+package terminal
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// click turns a click into the key it landed on: a key of the on-screen
+// keyboard while playing or an option of the post game menu.
+func (t *terminal) click(e keyEvent) (keyEvent, bool) {
+	l := t.render.layout
+	if l.tooSmall {
+		return keyEvent{}, false
+	}
+	if t.over {
+		return t.menuAt(l, e.row, e.col)
+	}
+
+	switch k := t.keyboard.at(l, e.row, e.col); k {
+	case "":
+		return keyEvent{}, false
+	case enterKey:
+		return keyEvent{kind: keyEnter}, true
+	case backspaceKey:
+		return keyEvent{kind: keyBackspace}, true
+	default:
+		r, _ := utf8.DecodeRuneInString(k)
+		return runeKey(r), true
+	}
+}
+
+// at returns the key drawn at row and col, if any.
+func (kb *keyboard) at(l layout, row, col int) string {
+	r := row - l.keyboardRow
+	if r < 0 || r >= len(keyboardLayout) {
+		return ""
+	}
+	i := col - l.keyboardColumn - l.keyboardIndent[r]
+	if i < 0 || i/l.tileWidth >= len(keyboardLayout[r]) {
+		return ""
+	}
+
+	return keyboardLayout[r][i/l.tileWidth]
+}
+
+// menuAt returns the key of the post game menu option at row and col,
+// the letter between parentheses of i.e. "(s)hare".
+func (t *terminal) menuAt(l layout, row, col int) (keyEvent, bool) {
+	if row != l.menuRow {
+		return keyEvent{}, false
+	}
+
+	menu := t.menuText(l)
+	x := l.center(menu)
+	for _, option := range strings.Fields(menu) {
+		n := utf8.RuneCountInString(option)
+		if col >= x && col < x+n && len(option) > 1 && option[0] == '(' {
+			return runeKey(rune(option[1])), true
+		}
+		x += n + 1
+	}
+
+	return keyEvent{}, false
+}
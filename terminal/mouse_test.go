@@ -0,0 +1,60 @@
+package terminal
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// clickAt returns the sequence of a left click at row and col, from 0.
+func clickAt(row, col int) string {
+	return fmt.Sprintf("\x1b[<0;%d;%dM", col+1, row+1)
+}
+
+func TestClick(t *testing.T) {
+	t.Run("clicking the keyboard types the keys", func(t *testing.T) {
+		terminal := newTestTerminal(io.Discard, strings.NewReader(""))
+		l := terminal.render.layout
+		key := func(row, i int) string {
+			return clickAt(l.keyboardRow+row, l.keyboardColumn+l.keyboardIndent[row]+i*l.tileWidth+1)
+		}
+
+		terminal.typeKeys(key(0, 0) + key(0, 1) + key(2, 8) + key(1, 0))
+		assert.Equal(t, []string{"Q", "A", "_", "_", "_"}, terminal.round.status)
+		assert.Equal(t, "A", terminal.keyboard.flashed)
+
+		terminal.typeKeys(key(2, 0))
+		assert.Contains(t, terminal.render.errQ, "Not enough letters")
+	})
+
+	t.Run("clicks outside the keys are ignored", func(t *testing.T) {
+		terminal := newTestTerminal(io.Discard, strings.NewReader(""))
+		l := terminal.render.layout
+		terminal.typeKeys(clickAt(l.keyboardRow, l.keyboardColumn) + clickAt(l.boardRow, l.boardColumn+1) + clickAt(l.keyboardRow+1, l.keyboardColumn+40))
+		assert.Equal(t, 0, terminal.round.index)
+	})
+
+	t.Run("clicking the post game menu", func(t *testing.T) {
+		terminal := newTestTerminal(io.Discard, strings.NewReader(""))
+		copied := ""
+		terminal.copy = func(s string) error { copied = s; return nil }
+		terminal.finish()
+		l := terminal.render.layout
+		x := l.center(postGameMenu)
+
+		terminal.typeKeys(clickAt(l.menuRow, x+3))
+		assert.Equal(t, shareMenu, terminal.menu)
+		terminal.typeKeys(clickAt(l.menuRow, l.center(shareMenu)))
+		assert.Equal(t, terminal.wordle.Share(), copied)
+		assert.False(t, terminal.quit)
+
+		terminal.typeKeys(clickAt(l.menuRow, x+len("(s)hare")))
+		assert.False(t, terminal.quit)
+
+		terminal.typeKeys(clickAt(l.menuRow, x+len("(s)hare (e)")))
+		assert.True(t, terminal.quit)
+	})
+}
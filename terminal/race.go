@@ -0,0 +1,65 @@
+package terminal
+
+import (
+	"github.com/Alvaroalonsobabbel/wordle/race"
+	"github.com/Alvaroalonsobabbel/wordle/wordle"
+)
+
+const (
+	raceWidth   = 13
+	raceNameLen = 10
+	raceSquare  = "  "
+)
+
+// racer connects the game to a head-to-head race.
+type racer interface {
+	Report(*wordle.Status) error
+	Updates() <-chan race.Update
+	Name() string
+}
+
+// WithRace shows the opponents progress and reports every guess to the race.
+func WithRace(r racer) ConfigSetter {
+	return func(t *terminal) {
+		t.racer = r
+	}
+}
+
+func (t *terminal) reportRace() {
+	if t.racer == nil {
+		return
+	}
+	if err := t.racer.Report(t.wordle); err != nil {
+		t.render.err("Lost connection to the race")
+	}
+}
+
+func (t *terminal) drawRace(s *screen, l layout) {
+	if t.racer == nil {
+		return
+	}
+
+	switch t.race.Winner {
+	case "":
+	case t.racer.Name():
+		s.print(l.raceRow, l.raceColumn, styleTitle, "You won the race!")
+	default:
+		s.print(l.raceRow, l.raceColumn, styleTitle, t.race.Winner+" won the race!")
+	}
+
+	for i, o := range t.race.Opponents {
+		column := l.raceColumn + 1 + i*raceWidth
+
+		name := []rune(o.Name)
+		if len(name) > raceNameLen {
+			name = name[:raceNameLen]
+		}
+		s.print(l.raceRow+1, column, styleDefault, string(name))
+
+		for r, row := range o.Rows {
+			for j, v := range row {
+				s.print(l.raceRow+2+r, column+j*len(raceSquare), tileStyle(v), l.square(tileStyle(v)))
+			}
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package terminal
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/Alvaroalonsobabbel/wordle/race"
+	"github.com/Alvaroalonsobabbel/wordle/wordle"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockRacer struct {
+	reported int
+	updates  chan race.Update
+}
+
+func (m *mockRacer) Report(s *wordle.Status) error {
+	m.reported = s.Round
+	return nil
+}
+
+func (m *mockRacer) Updates() <-chan race.Update { return m.updates }
+func (m *mockRacer) Name() string                { return "me" }
+
+func TestDrawRace(t *testing.T) {
+	terminal := newTestTerminal(io.Discard, strings.NewReader(""))
+	WithRace(&mockRacer{})(terminal)
+
+	tests := []struct {
+		name   string
+		update race.Update
+		want   []string
+	}{
+		{
+			name: "opponents progress without letters",
+			update: race.Update{Opponents: []race.Player{
+				{Name: "alice", Rows: [][]int{{wordle.Correct, wordle.Present, wordle.Absent, wordle.Absent, wordle.Absent}}},
+				{Name: "a very long name"},
+			}},
+			want: []string{"", "          alice        a very lon", ""},
+		},
+		{
+			name:   "an opponent won",
+			update: race.Update{Winner: "alice"},
+			want:   []string{"         alice won the race!", "", ""},
+		},
+		{
+			name:   "the player won",
+			update: race.Update{Winner: "me"},
+			want:   []string{"         You won the race!", "", ""},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s, l := newScreen(50, 24), newLayout(50, 24, true)
+			terminal.race = test.update
+			terminal.drawRace(s, l)
+			for i, want := range test.want {
+				assert.Equal(t, want, s.text(l.raceRow+i))
+			}
+		})
+	}
+
+	t.Run("opponents squares are colored by result", func(t *testing.T) {
+		s, l := newScreen(50, 24), newLayout(50, 24, true)
+		terminal.race = tests[0].update
+		terminal.drawRace(s, l)
+		for i, want := range []style{styleCorrect, styleCorrect, stylePresent, stylePresent, styleAbsent, styleAbsent} {
+			assert.Equal(t, want, s.style(l.raceRow+2, l.raceColumn+1+i))
+		}
+	})
+
+	t.Run("the race panel fits at the minimum height", func(t *testing.T) {
+		full := make([][]int, 6)
+		for i := range full {
+			full[i] = []int{wordle.Correct, wordle.Correct, wordle.Correct, wordle.Correct, wordle.Correct}
+		}
+		terminal.race = race.Update{Winner: "alice", Opponents: []race.Player{{Name: "alice", Rows: full}}}
+
+		for _, size := range [][2]int{{minWidth, minRaceHeight}, {minCompactWidth, minCompactRaceHeight}} {
+			s, l := newScreen(size[0], size[1]), newLayout(size[0], size[1], true)
+			assert.False(t, l.tooSmall, "%dx%d", size[0], size[1])
+			assert.Equal(t, size[1]-1, l.lastRow(), "%dx%d", size[0], size[1])
+
+			terminal.drawRace(s, l)
+			assert.Equal(t, styleCorrect, s.style(l.raceRow+raceHeight-1, l.raceColumn+1), "%dx%d", size[0], size[1])
+		}
+
+		assert.True(t, newLayout(minWidth, minRaceHeight-1, true).compact)
+		assert.True(t, newLayout(minCompactWidth, minCompactRaceHeight-1, true).tooSmall)
+	})
+}
+
+func TestReportRace(t *testing.T) {
+	t.Run("reports every guess", func(t *testing.T) {
+		racer := &mockRacer{}
+		terminal := newTestTerminal(io.Discard, strings.NewReader(""))
+		WithRace(racer)(terminal)
+
+		terminal.typeKeys("CHAIR\r")
+		assert.Equal(t, 1, racer.reported)
+	})
+}
@@ -3,83 +3,186 @@ package terminal
 import (
 	"fmt"
 	"io"
-	"sync"
+	"strings"
 	"time"
+
+	"github.com/Alvaroalonsobabbel/wordle/theme"
 )
 
 const (
 	errDuration = 1500 * time.Millisecond
-	errOffset   = 3
+
+	clearScreen = "\033[H\033[2J"
+	moveTo      = "\033[%d;%dH"
+	resetStyle  = "\x1b[0m"
 )
 
+// drawer is a component of the game that draws itself into the screen.
+type drawer interface {
+	draw(*screen, layout)
+}
+
+// render draws every component into a virtual screen and writes to w
+// only the cells that changed since the previous frame. It's only used
+// from the event loop so it needs no locking.
 type render struct {
-	errQ   []string
-	errCh  chan string
-	strCh  chan string
-	errDur time.Duration
-	w      io.Writer
-	wg     sync.WaitGroup
+	errQ    []string
+	errDur  time.Duration
+	loop    *loop
+	anim    Animation
+	w       io.Writer
+	drawers []drawer
+	layout  layout
+	styles  map[style]string
+	marks   map[style]string
+	front   *screen // what's displayed
+	back    *screen // what's being drawn
+	fresh   bool
+	// race lays out room for the race panel.
+	race bool
 }
 
-func newRender(w io.Writer) *render {
+func newRender(w io.Writer, l *loop) *render {
 	r := &render{
-		errCh:  make(chan string),
-		strCh:  make(chan string),
 		errDur: errDuration,
+		loop:   l,
 		w:      w,
+		front:  newScreen(defaultWidth, defaultHeight),
+		back:   newScreen(defaultWidth, defaultHeight),
+		fresh:  true,
 	}
-	go r.errMgr()
-	go r.strMgr()
+	r.setTheme(theme.Default, theme.ANSI)
 
 	return r
 }
 
+// setTheme changes how styles are displayed, it applies from the next frame.
+func (r *render) setTheme(th theme.Theme, p theme.Profile) {
+	r.styles, r.marks = styles(th, p)
+	r.layout = newLayout(r.front.width, r.front.height, r.race)
+	r.layout.marks = r.marks
+}
+
+// resize lays out the game for a screen of the given size and redraws it from scratch.
+func (r *render) resize(width, height int) {
+	if width == r.front.width && height == r.front.height {
+		return
+	}
+
+	r.layout = newLayout(width, height, r.race)
+	r.layout.marks = r.marks
+	r.front, r.back = newScreen(width, height), newScreen(width, height)
+	r.fresh = true
+	r.refresh()
+}
+
+// add registers components to be drawn, in order, on every refresh.
+func (r *render) add(d ...drawer) {
+	r.drawers = append(r.drawers, d...)
+}
+
 func (r *render) err(s string) {
-	r.wg.Add(1)
-	r.errCh <- s
+	r.errQ = append([]string{s}, r.errQ...)
+	r.loop.after(r.errDur, r.rmLastErr)
+	r.refresh()
 }
 
+// after runs f in the event loop once an animation step of d has
+// passed at the animation speed.
+func (r *render) after(d time.Duration, f func()) {
+	r.loop.after(r.anim.scale(d), f)
+}
+
+// string writes s as is, for escape sequences that are not part of the screen.
 func (r *render) string(s string) {
-	r.wg.Add(1)
-	r.strCh <- s
+	fmt.Fprint(r.w, s)
 }
 
-func (r *render) strMgr() {
-	for s := range r.strCh {
-		fmt.Fprint(r.w, s)
-		r.wg.Done()
+func (r *render) rmLastErr() {
+	if len(r.errQ) > 0 {
+		r.errQ = r.errQ[:len(r.errQ)-1]
+		r.refresh()
 	}
 }
 
-func (r *render) errMgr() {
-	for log := range r.errCh {
-		r.errQ = append([]string{log}, r.errQ...)
-		time.AfterFunc(r.errDur, r.rmLastErr)
-		r.printErrQ()
+// flush draws the last frame without the errors, which would otherwise
+// be left on the screen once the game exits.
+func (r *render) flush() {
+	r.errQ = nil
+	r.refresh()
+}
+
+func (r *render) draw(s *screen, l layout) {
+	for i, log := range r.errQ {
+		if i == l.errLines {
+			break
+		}
+
+		msg, col := " "+log+" ", l.errColumn
+		if l.compact {
+			col = l.center(msg)
+		}
+		s.print(l.errRow+i, col, styleError, msg)
 	}
 }
 
-func (r *render) rmLastErr() {
-	defer r.wg.Done()
+// drawTooSmall asks to resize the terminal when the game doesn't fit.
+func drawTooSmall(s *screen, l layout) {
+	row := max(0, l.height/2-1)
+	s.print(row, l.center(tooSmall), styleTitle, tooSmall)
+	s.print(row+1, l.center(resizeMsg), styleDefault, resizeMsg)
+}
 
-	r.errQ = r.errQ[:len(r.errQ)-1]
-	r.printErrQ()
+// refresh draws a new frame.
+func (r *render) refresh() {
+	r.back.clear()
+	if r.layout.tooSmall {
+		drawTooSmall(r.back, r.layout)
+	} else {
+		for _, d := range r.drawers {
+			d.draw(r.back, r.layout)
+		}
+		r.draw(r.back, r.layout)
+	}
+
+	fmt.Fprint(r.w, r.diff())
+	r.front, r.back = r.back, r.front
 }
 
-func (r *render) printErrQ() {
-	for i := range 6 {
-		fmt.Fprintf(r.w, "\033[%d;28H\033[K", i+errOffset)
+// diff returns the escape sequences that turn the front screen
+// into the back one, moving the cursor only when needed.
+func (r *render) diff() string {
+	var (
+		b        strings.Builder
+		st       = styleDefault
+		row, col = -1, -1
+	)
+
+	if r.fresh {
+		b.WriteString(clearScreen)
+		r.front.clear()
+		row, col, r.fresh = 0, 0, false
 	}
 
-	for i, log := range r.errQ {
-		if i < 6 {
-			fmt.Fprintf(r.w, "\033[%d;28H\x1b[3m\x1b[30m\x1b[47m %s \x1b[0m", i+errOffset, log)
+	for y := range r.back.cells {
+		for x, c := range r.back.cells[y] {
+			if c == r.front.cells[y][x] {
+				continue
+			}
+			if y != row || x != col {
+				fmt.Fprintf(&b, moveTo, y+1, x+1)
+			}
+			if c.style != st {
+				b.WriteString(resetStyle + r.styles[c.style])
+				st = c.style
+			}
+			b.WriteString(c.ch)
+			row, col = y, x+1
 		}
 	}
-}
+	if st != styleDefault {
+		b.WriteString(resetStyle)
+	}
 
-func (r *render) close() {
-	r.wg.Wait()
-	close(r.errCh)
-	close(r.strCh)
+	return b.String()
 }
@@ -3,44 +3,123 @@ package terminal
 import (
 	"bytes"
 	"io"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 )
 
+type mockDrawer struct {
+	text  string
+	style style
+}
+
+func (m *mockDrawer) draw(s *screen, _ layout) { s.print(1, 2, m.style, m.text) }
+
 func TestRender(t *testing.T) {
 	t.Run("errors are queued and removed from the queue after errDur", func(t *testing.T) {
-		render := newRender(io.Discard)
+		clock := &fakeClock{}
+		render := newRender(io.Discard, newLoop(clock))
 		render.errDur = 10 * time.Millisecond
 
 		for range 5 {
 			render.err("123")
 		}
-
-		time.Sleep(5 + time.Millisecond)
 		assert.Equal(t, 5, len(render.errQ))
 
-		render.wg.Wait()
+		render.loop.settle()
 		assert.Equal(t, 0, len(render.errQ))
+		assert.Equal(t, slices.Repeat([]time.Duration{10 * time.Millisecond}, 5), clock.waits())
 	})
 
-	t.Run("prints formatted err to w and clears error after", func(t *testing.T) {
+	t.Run("draws the err and clears it after", func(t *testing.T) {
 		buf := &bytes.Buffer{}
-		render := newRender(buf)
-		render.errDur = 10 * time.Millisecond
+		render := newRender(buf, newLoop(&fakeClock{}))
+
+		render.resize(50, 16)
+		buf.Reset()
+		l := render.layout
 
 		render.err("123")
-		render.wg.Wait()
-		assert.Equal(t, "\x1b[3;28H\x1b[K\x1b[4;28H\x1b[K\x1b[5;28H\x1b[K\x1b[6;28H\x1b[K\x1b[7;28H\x1b[K\x1b[8;28H\x1b[K\x1b[3;28H\x1b[3m\x1b[30m\x1b[47m 123 \x1b[0m\x1b[3;28H\x1b[K\x1b[4;28H\x1b[K\x1b[5;28H\x1b[K\x1b[6;28H\x1b[K\x1b[7;28H\x1b[K\x1b[8;28H\x1b[K", buf.String())
+		assert.Equal(t, strings.Repeat(" ", 21)+"123", render.front.text(l.errRow))
+		assert.Equal(t, styleError, render.front.style(l.errRow, l.errColumn))
+
+		render.loop.settle()
+		assert.Equal(t, "", render.front.text(l.errRow))
+		assert.Equal(t, "\x1b[3;21H\x1b[0m\x1b[3;30;47m 123 \x1b[0m\x1b[3;21H     ", buf.String())
+	})
+
+	t.Run("writes only what changed", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		render := newRender(buf, newLoop(&fakeClock{}))
+		d := &mockDrawer{text: "abc"}
+		render.add(d)
+
+		render.refresh()
+		assert.Equal(t, "\x1b[H\x1b[2J\x1b[2;3Habc", buf.String())
+
+		buf.Reset()
+		render.refresh()
+		assert.Equal(t, "", buf.String())
+
+		buf.Reset()
+		d.text, d.style = "abd", styleCorrect
+		render.refresh()
+		assert.Equal(t, "\x1b[2;3H\x1b[0m\x1b[7;32mabd\x1b[0m", buf.String())
+	})
+
+	t.Run("compact layout shows the last error centered", func(t *testing.T) {
+		render := newRender(io.Discard, newLoop(&fakeClock{}))
+		render.resize(30, 12)
+		render.errDur = time.Hour
+
+		render.err("first")
+		render.err("second")
+		assert.Equal(t, "            second", render.front.text(render.layout.errRow))
+		assert.Equal(t, "", render.front.text(render.layout.errRow+1))
+	})
+
+	t.Run("resizing redraws the whole screen", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		render := newRender(buf, newLoop(&fakeClock{}))
+		render.add(&mockDrawer{text: "abc"})
+		render.refresh()
+
+		buf.Reset()
+		render.resize(60, 20)
+		assert.Equal(t, "\x1b[H\x1b[2J\x1b[2;3Habc", buf.String())
+
+		buf.Reset()
+		render.resize(60, 20)
+		assert.Equal(t, "", buf.String(), "same size doesn't redraw")
+	})
+
+	t.Run("a terminal too small asks to resize", func(t *testing.T) {
+		render := newRender(io.Discard, newLoop(&fakeClock{}))
+		render.add(&mockDrawer{text: "abc"})
+		render.resize(20, 8)
+
+		assert.Equal(t, "", render.front.text(1))
+		assert.Equal(t, " Terminal too small", render.front.text(3))
+		assert.Equal(t, "   Please resize", render.front.text(4))
 	})
 
 	t.Run("prints str to w", func(t *testing.T) {
 		buf := &bytes.Buffer{}
-		render := newRender(buf)
+		render := newRender(buf, newLoop(&fakeClock{}))
 
 		render.string("123")
-		render.wg.Wait()
 		assert.Equal(t, "123", buf.String())
 	})
+
+	t.Run("flushing clears the errors", func(t *testing.T) {
+		render := newRender(io.Discard, newLoop(&fakeClock{}))
+		render.err("123")
+		render.flush()
+
+		assert.Empty(t, render.errQ)
+		assert.Equal(t, "", render.front.text(render.layout.errRow))
+	})
 }
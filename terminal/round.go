@@ -1,24 +1,32 @@
 package terminal
 
 import (
-	"fmt"
-	"time"
+	"slices"
+	"strings"
 
 	"github.com/Alvaroalonsobabbel/wordle/wordle"
 )
 
-const (
-	moveToYX    = "\033[%d;%dH%s"
-	roundPos    = "\033[%d;9H"
-	roundOffset = 3
-)
+const emptyTile = "_"
+
+const edgeTile = "─"
 
 type round struct {
-	index     int
-	status    []string
-	animation string
-	wordle    *wordle.Status
-	render    *render
+	// index is how many letters have been typed.
+	index int
+	// cursor is where the next letter is typed, from 0 to index.
+	cursor int
+	status []string
+	// offset moves the current row to the right while shaking.
+	offset int
+	// reveal is the letter of the last result being revealed, -1 when the result is not being revealed.
+	reveal int
+	// flip shows the letter being revealed edge-on, halfway through flipping.
+	flip bool
+	// bounce is the letter of the winning row lifted up, -1 when not bouncing.
+	bounce int
+	wordle *wordle.Status
+	render *render
 }
 
 func newRound(w *wordle.Status, r *render) *round { //nolint: revive
@@ -26,104 +34,213 @@ func newRound(w *wordle.Status, r *render) *round { //nolint: revive
 		render: r,
 		wordle: w,
 		status: []string{"_", "_", "_", "_", "_"},
+		reveal: -1,
+		bounce: -1,
 	}
 }
 
-func (r *round) print(round int) {
-	p := fmt.Sprintf(roundPos, round+roundOffset)
-
-	if round < len(r.wordle.Results) {
-		for _, b := range r.wordle.Results[round] {
-			for k, v := range b {
-				var color string
-				switch v {
-				case wordle.Correct:
-					color = greenBackground
-				case wordle.Present:
-					color = yellowBackground
-				case wordle.Absent:
-					color = greyBackground
+func (r *round) draw(s *screen, l layout) {
+	last := len(r.wordle.Results) - 1
+
+	for row := range 6 {
+		y := l.boardRow + row
+
+		switch {
+		case row < len(r.wordle.Results):
+			for i, res := range r.wordle.Results[row] {
+				for k, v := range res {
+					st, letter, x := tileStyle(v), string(k), l.boardColumn+i*l.tileWidth
+					if row == last && r.reveal >= 0 {
+						switch {
+						case i == r.reveal && r.flip:
+							st, letter = styleDefault, edgeTile
+						case i > r.reveal:
+							st = styleDefault
+						}
+					}
+					tileRow := y
+					if row == last && i == r.bounce {
+						s.print(y, x, styleDefault, l.tile(" ", styleDefault))
+						tileRow--
+					}
+					s.print(tileRow, x, st, l.tile(letter, st))
 				}
-				p += fmt.Sprintf(color, string(k))
 			}
-		}
-	} else if r.wordle.Round < 6 {
-		p += r.animation
-		for _, s := range r.status {
-			p += fmt.Sprintf(emptyChar, s)
+		case row == r.wordle.Round:
+			for i, letter := range r.status {
+				st := styleDefault
+				// The cursor is only shown once it's moved away from the end.
+				if i == r.cursor && r.cursor < r.index {
+					st = styleCursor
+				}
+				s.print(y, l.boardColumn+r.offset+i*l.tileWidth, st, l.tile(letter, st))
+			}
+		default:
+			for i := range 5 {
+				s.print(y, l.boardColumn+i*l.tileWidth, styleDefault, l.tile(emptyTile, styleDefault))
+			}
 		}
 	}
+}
 
-	r.render.string(p + " ") // Trailing space to delete the error animation tail.
+func tileStyle(result int) style {
+	switch result {
+	case wordle.Correct:
+		return styleCorrect
+	case wordle.Present:
+		return stylePresent
+	default:
+		return styleAbsent
+	}
 }
 
 func (r *round) shake() {
-	go func() {
-		for i := range 6 {
-			if i%2 == 0 {
-				r.animation = " "
-			} else {
-				r.animation = ""
-			}
+	if !r.render.anim.moves() {
+		return
+	}
 
-			r.print(r.wordle.Round)
-			time.Sleep(50 * time.Millisecond)
+	var step func(i int)
+	step = func(i int) {
+		if i == 6 {
+			return
 		}
-	}()
-}
-
-func (r *round) renderResult() {
-	defer r.reset()
-
-	var (
-		// results are displyed after wordle.Try increments the internal
-		// round counter that's why we run it with wordle.Round - 1
-		round = r.wordle.Round - 1
-		row   = round + roundOffset
-	)
-
-	for i, res := range r.wordle.Results[round] {
-		for k, v := range res {
-			color := greyBackground
-			switch v {
-			case wordle.Correct:
-				color = greenBackground
-			case wordle.Present:
-				color = yellowBackground
-			}
+		r.offset = (i + 1) % 2
+		r.render.refresh()
+		r.render.after(shakeStep, func() { step(i + 1) })
+	}
+	step(0)
+}
+
+// renderResult reveals the last result and calls done once it's displayed.
+func (r *round) renderResult(done func()) {
+	revealed := func() {
+		r.reveal = -1
+		r.reset()
+		r.render.refresh()
+
+		if r.render.anim.moves() && string(r.wordle.Discovered[:]) == r.wordle.Wordle {
+			r.bounceRow(done)
+			return
+		}
+		done()
+	}
+	if !r.render.anim.moves() {
+		revealed()
+		return
+	}
+
+	// results are displyed after wordle.Try increments the internal round
+	// counter, the letters are flipped one at a time in two steps each.
+	var step func(i int)
+	step = func(i int) {
+		if i == 10 {
+			revealed()
+			return
+		}
+		r.reveal, r.flip = i/2, i%2 == 0
+		r.render.refresh()
+		r.render.after(revealDuration/2, func() { step(i + 1) })
+	}
+	step(0)
+}
 
-			// 9 is the distance between the marging and the round.
-			// 3 is the spaces each letter occupies.
-			r.render.string(fmt.Sprintf(moveToYX, row, 9+(i*3), " _ "))
-			time.Sleep(250 * time.Millisecond)
-			r.render.string(fmt.Sprintf(moveToYX, row, 9+(i*3), fmt.Sprintf(color, string(k))))
+// bounceRow lifts the letters of the winning row one after the other.
+func (r *round) bounceRow(done func()) {
+	var step func(i int)
+	step = func(i int) {
+		if i == 5 {
+			r.bounce = -1
+			r.render.refresh()
+			done()
+			return
 		}
+		r.bounce = i
+		r.render.refresh()
+		r.render.after(bounceStep, func() { step(i + 1) })
 	}
+	step(0)
 }
 
 func (r *round) add(s string) {
-	defer r.print(r.wordle.Round)
+	defer r.render.refresh()
 
 	if r.index == 5 {
 		return
 	}
 
-	r.status[r.index] = s
-	r.index++
+	r.set(slices.Insert(r.letters(), r.cursor, s), r.cursor+1)
 }
 
+// paste types the letters in text, anything else is ignored.
+func (r *round) paste(text string) {
+	defer r.render.refresh()
+
+	letters := r.letters()
+	cursor := r.cursor
+	for _, c := range strings.ToUpper(text) {
+		if len(letters) == 5 {
+			break
+		}
+		if c >= 'A' && c <= 'Z' {
+			letters = slices.Insert(letters, cursor, string(c))
+			cursor++
+		}
+	}
+	r.set(letters, cursor)
+}
+
+// backspace deletes the letter before the cursor.
 func (r *round) backspace() {
-	defer r.print(r.wordle.Round)
+	defer r.render.refresh()
+
+	if r.cursor == 0 {
+		return
+	}
+
+	r.set(slices.Delete(r.letters(), r.cursor-1, r.cursor), r.cursor-1)
+}
+
+// delete deletes the letter under the cursor.
+func (r *round) delete() {
+	defer r.render.refresh()
 
-	if r.index == 0 {
+	if r.cursor == r.index {
 		return
 	}
 
-	r.index--
-	r.status[r.index] = "_"
+	r.set(slices.Delete(r.letters(), r.cursor, r.cursor+1), r.cursor)
+}
+
+// deleteToStart deletes the letters before the cursor. A row is a single
+// word, so it's what both Ctrl-U and Ctrl-W do.
+func (r *round) deleteToStart() {
+	defer r.render.refresh()
+
+	r.set(r.letters()[r.cursor:], 0)
+}
+
+// move places the cursor at i, within the typed letters.
+func (r *round) move(i int) {
+	defer r.render.refresh()
+
+	r.cursor = max(0, min(i, r.index))
+}
+
+func (r *round) letters() []string {
+	return slices.Clone(r.status[:r.index])
+}
+
+func (r *round) set(letters []string, cursor int) {
+	r.index, r.cursor = len(letters), cursor
+	for i := range r.status {
+		r.status[i] = emptyTile
+		if i < len(letters) {
+			r.status[i] = letters[i]
+		}
+	}
 }
 
 func (r *round) reset() {
-	r.index = 0
+	r.index, r.cursor = 0, 0
 	r.status = []string{"_", "_", "_", "_", "_"}
 }
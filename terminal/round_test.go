@@ -1,7 +1,6 @@
 package terminal
 
 import (
-	"bytes"
 	"io"
 	"testing"
 
@@ -9,56 +8,77 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func TestRoundPrint(t *testing.T) {
+func TestRoundDraw(t *testing.T) {
 	wordle := &wordle.Status{Wordle: "CHORE"}
-	buf := &bytes.Buffer{}
-	render := newRender(buf)
-	round := newRound(wordle, render)
+	round := newRound(wordle, newRender(io.Discard, newLoop(&fakeClock{})))
+	l := newLayout(50, 16, false)
+	draw := func() *screen {
+		s := newScreen(50, 16)
+		round.draw(s, l)
+		return s
+	}
 
 	t.Run("emtpy rounds", func(t *testing.T) {
-		round.print(0)
-		render.wg.Wait()
-		want := "\x1b[3;9H _  _  _  _  _  "
-		assert.Equal(t, want, buf.String())
+		s := draw()
+		for i := range 6 {
+			assert.Equal(t, "                  _  _  _  _  _", s.text(l.boardRow+i))
+		}
 	})
 
-	t.Run("with one letter", func(t *testing.T) {
-		buf.Reset()
+	t.Run("with two letters", func(t *testing.T) {
 		round.add("A")
-		render.wg.Wait()
-		want := "\x1b[3;9H A  _  _  _  _  "
-		assert.Equal(t, want, buf.String())
+		round.add("B")
+		assert.Equal(t, "                  A  B  _  _  _", draw().text(l.boardRow))
 	})
 
-	t.Run("with two letters and animations space", func(t *testing.T) {
-		buf.Reset()
-		round.add("A")
-		render.wg.Wait()
-		want := "\x1b[3;9H A  A  _  _  _  "
-		assert.Equal(t, want, buf.String())
+	t.Run("shaking moves the current row", func(t *testing.T) {
+		round.offset = 1
+		defer func() { round.offset = 0 }()
+		assert.Equal(t, "                   A  B  _  _  _", draw().text(l.boardRow))
 	})
 
-	t.Run("after a round exist in wordle status it prints the status with color", func(t *testing.T) {
-		buf.Reset()
+	t.Run("after a round exist in wordle status it draws the status with color", func(t *testing.T) {
 		wordle.Try("SCORE") //nolint: errcheck
-		round.print(0)
-		render.wg.Wait()
-		want := "\x1b[3;9H\x1b[7m\x1b[90m S \x1b[0m\x1b[7m\x1b[33m C \x1b[0m\x1b[7m\x1b[32m O \x1b[0m\x1b[7m\x1b[32m R \x1b[0m\x1b[7m\x1b[32m E \x1b[0m "
-		assert.Equal(t, want, buf.String())
-
-		buf.Reset()
 		round.reset()
-		round.print(1)
-		render.wg.Wait()
-		want = "\x1b[4;9H _  _  _  _  _  "
-		assert.Equal(t, want, buf.String())
+		s := draw()
+		assert.Equal(t, "                  S  C  O  R  E", s.text(l.boardRow))
+		assert.Equal(t, "                  _  _  _  _  _", s.text(l.boardRow+1))
+		for i, want := range []style{styleAbsent, stylePresent, styleCorrect, styleCorrect, styleCorrect} {
+			assert.Equal(t, want, s.style(l.boardRow, l.boardColumn+i*l.tileWidth+1))
+		}
+	})
+
+	t.Run("while revealing the result only the revealed letters are colored", func(t *testing.T) {
+		round.reveal, round.flip = 2, true
+		defer func() { round.reveal, round.flip = -1, false }()
+		s := draw()
+		assert.Equal(t, "                  S  C  ─  R  E", s.text(l.boardRow))
+		assert.Equal(t, stylePresent, s.style(l.boardRow, l.boardColumn+l.tileWidth+1))
+		assert.Equal(t, styleDefault, s.style(l.boardRow, l.boardColumn+3*l.tileWidth+1))
+	})
+
+	t.Run("the bouncing letter is lifted a row", func(t *testing.T) {
+		round.bounce = 1
+		defer func() { round.bounce = -1 }()
+		s := draw()
+		assert.Equal(t, "                     C", s.text(l.boardRow-1))
+		assert.Equal(t, "                  S     O  R  E", s.text(l.boardRow))
+		assert.Equal(t, stylePresent, s.style(l.boardRow-1, l.boardColumn+l.tileWidth+1))
+	})
+
+	t.Run("compact layout", func(t *testing.T) {
+		l = newLayout(30, 12, false)
+		s := draw()
+		assert.Equal(t, "          S C O R E", s.text(l.boardRow))
+		assert.Equal(t, "          _ _ _ _ _", s.text(l.boardRow+1))
+		assert.Equal(t, stylePresent, s.style(l.boardRow, l.boardColumn+l.tileWidth))
 	})
 }
 
 func TestAdd(t *testing.T) {
 	t.Run("adding one letter", func(t *testing.T) {
 		wordle := &wordle.Status{Wordle: "CHORE"}
-		render := newRender(io.Discard)
+		render := newRender(io.Discard, newLoop(&fakeClock{}))
 		round := newRound(wordle, render)
 
 		round.add("A")
@@ -67,7 +87,7 @@ func TestAdd(t *testing.T) {
 
 	t.Run("adding five consecutive letters", func(t *testing.T) {
 		wordle := &wordle.Status{Wordle: "CHORE"}
-		render := newRender(io.Discard)
+		render := newRender(io.Discard, newLoop(&fakeClock{}))
 		round := newRound(wordle, render)
 		letters := []string{"A", "B", "C", "D", "E"}
 
@@ -82,7 +102,7 @@ func TestAdd(t *testing.T) {
 
 	t.Run("adding more than 5 letters does not increment the counter nor adds another letter", func(t *testing.T) {
 		wordle := &wordle.Status{Wordle: "CHORE"}
-		render := newRender(io.Discard)
+		render := newRender(io.Discard, newLoop(&fakeClock{}))
 		round := newRound(wordle, render)
 		letters := []string{"A", "B", "C", "D", "E", "F"}
 
@@ -100,7 +120,7 @@ func TestAdd(t *testing.T) {
 func TestBackspace(t *testing.T) {
 	t.Run("reverts the counter and replaces the letter with underscore", func(t *testing.T) {
 		w := &wordle.Status{Wordle: "CHORE"}
-		r := newRender(io.Discard)
+		r := newRender(io.Discard, newLoop(&fakeClock{}))
 		round := newRound(w, r)
 		round.add("A")
 		round.add("B")
@@ -112,7 +132,7 @@ func TestBackspace(t *testing.T) {
 
 	t.Run("when counter is 0, backspace has no effect", func(t *testing.T) {
 		w := &wordle.Status{Wordle: "CHORE"}
-		r := newRender(io.Discard)
+		r := newRender(io.Discard, newLoop(&fakeClock{}))
 		round := newRound(w, r)
 		round.backspace()
 
@@ -120,3 +140,48 @@ func TestBackspace(t *testing.T) {
 		assert.Equal(t, 0, round.index)
 	})
 }
+
+func TestCursor(t *testing.T) {
+	typed := func(letters string) *round {
+		r := newRound(&wordle.Status{Wordle: "CHORE"}, newRender(io.Discard, newLoop(&fakeClock{})))
+		for _, l := range letters {
+			r.add(string(l))
+		}
+		return r
+	}
+
+	tests := []struct {
+		name       string
+		edit       func(r *round)
+		want       []string
+		wantCursor int
+	}{
+		{"letters are inserted at the cursor", func(r *round) { r.move(1); r.add("X") }, []string{"A", "X", "B", "C", "_"}, 2},
+		{"backspace deletes before the cursor", func(r *round) { r.move(1); r.backspace() }, []string{"B", "C", "_", "_", "_"}, 0},
+		{"delete deletes under the cursor", func(r *round) { r.move(1); r.delete() }, []string{"A", "C", "_", "_", "_"}, 1},
+		{"delete at the end has no effect", func(r *round) { r.delete() }, []string{"A", "B", "C", "_", "_"}, 3},
+		{"deleting to the start", func(r *round) { r.move(2); r.deleteToStart() }, []string{"C", "_", "_", "_", "_"}, 0},
+		{"the cursor stays within the letters", func(r *round) { r.move(-1); r.move(9) }, []string{"A", "B", "C", "_", "_"}, 3},
+		{"pasting keeps the letters that fit", func(r *round) { r.move(0); r.paste("x-y z!") }, []string{"X", "Y", "A", "B", "C"}, 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := typed("ABC")
+			test.edit(r)
+			assert.Equal(t, test.want, r.status)
+			assert.Equal(t, test.wantCursor, r.cursor)
+		})
+	}
+
+	t.Run("the cursor is shown once moved", func(t *testing.T) {
+		r, l := typed("ABC"), newLayout(50, 16, false)
+		s := newScreen(50, 16)
+		r.draw(s, l)
+		assert.Equal(t, styleDefault, s.style(l.boardRow, l.boardColumn+3*l.tileWidth+1))
+
+		r.move(1)
+		r.draw(s, l)
+		assert.Equal(t, styleCursor, s.style(l.boardRow, l.boardColumn+l.tileWidth+1))
+	})
+}
@@ -0,0 +1,92 @@
+package terminal
+
+import (
+	"strings"
+)
+
+const (
+	defaultWidth  = 80
+	defaultHeight = 24
+)
+
+// style is what a cell looks like, the render translates it into escape sequences.
+type style int
+
+const (
+	styleDefault style = iota
+	styleTitle
+	styleFooter
+	styleCorrect
+	stylePresent
+	styleAbsent
+	styleFlash
+	styleError
+	styleCursor
+)
+
+type cell struct {
+	ch    string
+	style style
+}
+
+var blank = cell{ch: " "}
+
+// screen is a grid of cells the components of the game draw into.
+type screen struct {
+	width, height int
+	cells         [][]cell
+}
+
+func newScreen(width, height int) *screen {
+	s := &screen{width: width, height: height, cells: make([][]cell, height)}
+	for i := range s.cells {
+		s.cells[i] = make([]cell, width)
+	}
+	s.clear()
+
+	return s
+}
+
+func (s *screen) clear() {
+	for _, row := range s.cells {
+		for i := range row {
+			row[i] = blank
+		}
+	}
+}
+
+// print writes text starting at the 0-based row and col, anything
+// falling outside the screen is clipped. Variation selectors are kept
+// with the previous character since they take no space.
+func (s *screen) print(row, col int, st style, text string) {
+	if row < 0 || row >= s.height {
+		return
+	}
+
+	for _, r := range text {
+		if r == '\ufe0e' || r == '\ufe0f' {
+			if c := col - 1; c >= 0 && c < s.width {
+				s.cells[row][c].ch += string(r)
+			}
+			continue
+		}
+		if col >= 0 && col < s.width {
+			s.cells[row][col] = cell{ch: string(r), style: st}
+		}
+		col++
+	}
+}
+
+// text returns the text of a row without trailing spaces.
+func (s *screen) text(row int) string {
+	var b strings.Builder
+	for _, c := range s.cells[row] {
+		b.WriteString(c.ch)
+	}
+
+	return strings.TrimRight(b.String(), " ")
+}
+
+func (s *screen) style(row, col int) style {
+	return s.cells[row][col].style
+}
@@ -0,0 +1,98 @@
+package terminal
+
+import (
+	"fmt"
+	"os"
+	"unicode"
+
+	"github.com/Alvaroalonsobabbel/wordle/wordle"
+)
+
+const (
+	shareMenu = "(n)yt (s)poiler (a)scii (i)mage"
+	// compactShareMenu is the share menu squeezed in the compact layout.
+	compactShareMenu = "(n)yt (s)p (a)sc (i)mg"
+)
+
+// shareFormats are the formats of the share menu by key.
+var shareFormats = map[rune]wordle.ShareFormat{
+	'n': wordle.ShareNYT,
+	's': wordle.ShareSpoiler,
+	'a': wordle.ShareASCII,
+}
+
+// WithImageWriter replaces how the image of the board is saved, which is
+// a file in the current directory by default. nil disables images.
+func WithImageWriter(w func(name string, png []byte) error) ConfigSetter {
+	return func(t *terminal) {
+		t.writeImage = w
+	}
+}
+
+// WithDarkSquares shares the absent letters with the black squares of the
+// NYT dark mode.
+func WithDarkSquares() ConfigSetter {
+	return func(t *terminal) {
+		wordle.WithDarkSquares()(t.wordle)
+	}
+}
+
+func writeImage(name string, png []byte) error {
+	return os.WriteFile(name, png, 0600)
+}
+
+// share copies the result in the format chosen in the share menu or
+// saves its image, Escape goes back to the post game menu.
+func (t *terminal) share(e keyEvent) {
+	if e.kind == keyEscape {
+		t.postGameMenu()
+		return
+	}
+	if e.kind != keyRune {
+		return
+	}
+
+	r := unicode.ToLower(e.r)
+	format, ok := shareFormats[r]
+	switch {
+	case ok:
+		if err := t.copy(t.wordle.ShareAs(format)); err != nil {
+			t.render.err("Unable to copy to Clipboard")
+			break
+		}
+		t.render.err("Copied to Clipboard!")
+	case r == 'i':
+		t.render.err(t.shareImage())
+	default:
+		return
+	}
+	t.postGameMenu()
+}
+
+// shareImage saves the image of the board, returning the message telling
+// how it went.
+func (t *terminal) shareImage() string {
+	if t.writeImage == nil {
+		return "Images can't be saved here"
+	}
+
+	png, err := t.wordle.ShareImage()
+	if err != nil {
+		return "Unable to save the image"
+	}
+	name := fmt.Sprintf("wordle-%d.png", t.wordle.PuzzleNumber)
+	if err := t.writeImage(name, png); err != nil {
+		return "Unable to save the image"
+	}
+
+	return "Saved " + name
+}
+
+// menuText is the menu displayed in layout l.
+func (t *terminal) menuText(l layout) string {
+	if l.compact && t.menu == shareMenu {
+		return compactShareMenu
+	}
+
+	return t.menu
+}
@@ -0,0 +1,92 @@
+package terminal
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/Alvaroalonsobabbel/wordle/wordle"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShare(t *testing.T) {
+	newFinished := func() (*terminal, *string) {
+		terminal := newTestTerminal(io.Discard, strings.NewReader(""))
+		assert.NoError(t, terminal.wordle.Try("CHORE"))
+		copied := new(string)
+		terminal.copy = func(s string) error { *copied = s; return nil }
+		terminal.finish()
+		return terminal, copied
+	}
+
+	for key, format := range map[string]wordle.ShareFormat{"n": wordle.ShareNYT, "s": wordle.ShareSpoiler, "A": wordle.ShareASCII} {
+		t.Run("copies the "+string(format)+" format", func(t *testing.T) {
+			terminal, copied := newFinished()
+			terminal.typeKeys("s")
+			assert.Equal(t, shareMenu, strings.TrimSpace(terminal.render.front.text(terminal.render.layout.menuRow)))
+
+			terminal.typeKeys(key)
+			assert.Equal(t, terminal.wordle.ShareAs(format), *copied)
+			assert.Contains(t, terminal.render.errQ, "Copied to Clipboard!")
+			assert.Equal(t, postGameMenu, terminal.menu, "back to the post game menu")
+		})
+	}
+
+	t.Run("copies dark squares", func(t *testing.T) {
+		terminal := newTestTerminal(io.Discard, strings.NewReader(""))
+		WithDarkSquares()(terminal)
+		var copied string
+		terminal.copy = func(s string) error { copied = s; return nil }
+		assert.NoError(t, terminal.wordle.Try("SCORE"))
+		assert.NoError(t, terminal.wordle.Try("CHORE"))
+		terminal.finish()
+
+		terminal.typeKeys("sn")
+		assert.Contains(t, copied, "⬛")
+		assert.NotContains(t, copied, "⬜")
+	})
+
+	t.Run("escape goes back without sharing", func(t *testing.T) {
+		terminal, copied := newFinished()
+		terminal.typeKeys("sx\x1b")
+		assert.Empty(t, *copied)
+		assert.Equal(t, postGameMenu, terminal.menu)
+		assert.False(t, terminal.quit)
+	})
+
+	t.Run("saves the image", func(t *testing.T) {
+		terminal, _ := newFinished()
+		terminal.wordle.PuzzleNumber = 1234
+		var name string
+		var png []byte
+		WithImageWriter(func(n string, data []byte) error { name, png = n, data; return nil })(terminal)
+
+		terminal.typeKeys("si")
+		assert.Equal(t, "wordle-1234.png", name)
+		assert.NotEmpty(t, png)
+		assert.Contains(t, terminal.render.errQ, "Saved wordle-1234.png")
+	})
+
+	t.Run("image errors are shown", func(t *testing.T) {
+		terminal, _ := newFinished()
+		WithImageWriter(func(string, []byte) error { return errors.New("read-only file system") })(terminal)
+		terminal.typeKeys("si")
+		assert.Contains(t, terminal.render.errQ, "Unable to save the image")
+
+		WithImageWriter(nil)(terminal)
+		terminal.typeKeys("si")
+		assert.Contains(t, terminal.render.errQ, "Images can't be saved here")
+	})
+
+	t.Run("the compact layout squeezes the menu", func(t *testing.T) {
+		terminal, copied := newFinished()
+		terminal.render.resize(minCompactWidth, minCompactHeight)
+		terminal.typeKeys("s")
+		l := terminal.render.layout
+		assert.Equal(t, compactShareMenu, strings.TrimSpace(terminal.render.front.text(l.menuRow)))
+
+		terminal.typeKeys(clickAt(l.menuRow, l.center(compactShareMenu)+len("(n)yt (s)")))
+		assert.Equal(t, terminal.wordle.ShareAs(wordle.ShareSpoiler), *copied)
+	})
+}
@@ -0,0 +1,25 @@
+package terminal
+
+import (
+	"os"
+	"os/signal"
+)
+
+// WithSignals ends the game when c receives a signal instead of when the
+// process is asked to terminate, i.e. an SSH session sharing the process
+// with others. A nil channel leaves the signals alone.
+func WithSignals(c <-chan os.Signal) ConfigSetter {
+	return func(t *terminal) {
+		t.signals = func() (<-chan os.Signal, func()) { return c, func() {} }
+	}
+}
+
+// notifyShutdown receives the signals asking the process to terminate,
+// until stopped. Until then they no longer kill the process, so the game
+// ends like when the player quits: restoring the console and saving.
+func notifyShutdown() (<-chan os.Signal, func()) {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, shutdownSignals...)
+
+	return c, func() { signal.Stop(c) }
+}
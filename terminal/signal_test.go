@@ -0,0 +1,55 @@
+package terminal
+
+import (
+	"io"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/Alvaroalonsobabbel/wordle/wordle"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignals(t *testing.T) {
+	t.Run("a shutdown signal restores the console and saves the game", func(t *testing.T) {
+		r, w := io.Pipe()
+		defer w.Close()
+		signals, tty, saver := make(chan os.Signal), &mockTTY{}, &mockSaver{}
+		terminal := New(&wordle.Status{Wordle: "HELLO"}, WithInput(r), WithOutput(io.Discard), WithTTY(tty), WithSaver(saver), WithSignals(signals))
+
+		done := make(chan error)
+		go func() { done <- terminal.Start() }()
+		signals <- syscall.SIGTERM
+
+		assert.NoError(t, <-done)
+		assert.True(t, tty.restored)
+		assert.NotNil(t, saver.saved)
+	})
+
+	t.Run("a shutdown signal saves the accessible game", func(t *testing.T) {
+		r, w := io.Pipe()
+		defer w.Close()
+		signals, saver := make(chan os.Signal), &mockSaver{}
+		terminal := New(&wordle.Status{Wordle: "HELLO"}, WithInput(r), WithOutput(io.Discard), WithTTY(nil), WithSaver(saver), WithSignals(signals), WithAccessible())
+
+		done := make(chan error)
+		go func() { done <- terminal.Start() }()
+		signals <- syscall.SIGHUP
+
+		assert.NoError(t, <-done)
+		assert.NotNil(t, saver.saved)
+	})
+
+	t.Run("panics restore the console and save the game", func(t *testing.T) {
+		game := &wordle.Status{Wordle: "HELLO"}
+		assert.NoError(t, game.Try("HELLO"))
+		tty, saver := &mockTTY{}, &mockSaver{}
+		terminal := New(game, WithInput(strings.NewReader("sn")), WithOutput(io.Discard), WithTTY(tty), WithSaver(saver), WithSignals(nil))
+		terminal.copy = func(string) error { panic("clipboard") }
+
+		assert.PanicsWithValue(t, "clipboard", func() { terminal.Start() }) //nolint: errcheck
+		assert.True(t, tty.restored)
+		assert.NotNil(t, saver.saved)
+	})
+}
@@ -1,160 +1,380 @@
 package terminal
 
 import (
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
-	"log"
 	"os"
-	"regexp"
 	"strings"
+	"unicode"
 
+	"github.com/Alvaroalonsobabbel/wordle/leaderboard"
+	"github.com/Alvaroalonsobabbel/wordle/race"
 	"github.com/Alvaroalonsobabbel/wordle/status"
+	"github.com/Alvaroalonsobabbel/wordle/theme"
 	"github.com/Alvaroalonsobabbel/wordle/wordle"
 	"github.com/atotto/clipboard"
-	"golang.org/x/term"
 )
 
 const (
-	// Relevant unicode characters to control the game.
-	enter     = 13
-	backspace = 127
-	ctrlC     = 3
-
-	// Accepted characters regex.
-	okRegex = `^[A-Z]$`
-
-	title            = "\033[H\033[2J\033[1m6 attempts to find a 5-letter word\n\033[0m"
-	postGameMenu     = "\033[15;0H(s)hare (e)xit"
-	italicFooter     = "\033[10;0H\x1b[3m%s\x1b[0m"
-	greenBackground  = "\x1b[7m\x1b[32m %s \x1b[0m"
-	yellowBackground = "\x1b[7m\x1b[33m %s \x1b[0m"
-	greyBackground   = "\x1b[7m\x1b[90m %s \x1b[0m"
+	postGameMenu     = "(s)hare (e)xit"
+	postGameMenuPost = "(s)hare (p)ost (e)xit"
 	hideCursor       = "\033[?25l"
-	showCursor       = "\033[13;0H\n\r\033[?25h"
-	emptyChar        = " %s "
+	showCursor       = "\033[?25h"
+	osc52            = "\033]52;c;%s\a"
 )
 
 var finishMessage = []string{"Genius", "Magnificent", "Impressive", "Splendid", "Great", "Phew!"}
 
+// poster posts finished games to a leaderboard.
+type poster interface {
+	Post(*wordle.Status) error
+}
+
+// saver persists the game when the terminal exits.
+type saver interface {
+	SaveGame(*wordle.Status) error
+}
+
 type terminal struct {
-	wordle   *wordle.Status
-	keyboard *keyboard
-	round    *round
-	render   *render
-	reader   io.Reader
+	footer     string
+	menu       string
+	wordle     *wordle.Status
+	keyboard   *keyboard
+	round      *round
+	render     *render
+	reader     io.Reader
+	writer     io.Writer
+	tty        TTY
+	copy       func(string) error
+	writeImage func(name string, png []byte) error
+	poster     poster
+	posting    bool
+	saver      saver
+	racer      racer
+	race       race.Update
+	theme      theme.Theme
+	colors     theme.Profile
+	accessible bool
+	animation  Animation
+	// signals subscribes to the signals ending the game.
+	signals func() (<-chan os.Signal, func())
+	loop    *loop
+	// busy is set while a result is being revealed, the keys pressed
+	// meanwhile are kept in pending.
+	busy    bool
+	pending []keyEvent
+	over    bool
+	quit    bool
+}
+
+type ConfigSetter func(*terminal)
+
+// WithInput reads the key presses from r instead of the standard input.
+func WithInput(r io.Reader) ConfigSetter {
+	return func(t *terminal) {
+		t.reader = r
+	}
 }
 
-func New(w *wordle.Status) *terminal { //nolint: revive
-	r := newRender(os.Stdout)
+// WithOutput renders the game to w instead of the standard output.
+func WithOutput(w io.Writer) ConfigSetter {
+	return func(t *terminal) {
+		t.writer = w
+	}
+}
 
-	return &terminal{
-		reader:   os.Stdin,
-		wordle:   w,
-		render:   r,
-		round:    newRound(w, r),
-		keyboard: newKeyboard(w, r),
+// WithTTY replaces the controller of the terminal the game is displayed
+// in, i.e. an SSH session pty. With a nil TTY the input is expected to
+// deliver every key press as it's typed and the size is unknown.
+func WithTTY(tty TTY) ConfigSetter {
+	return func(t *terminal) {
+		t.tty = tty
 	}
 }
 
-func (t *terminal) Start() {
-	restoreConsole := startRawConsole()
+// WithOSC52Clipboard copies the shared result with the OSC 52 escape
+// sequence, which sets the clipboard of the terminal emulator displaying
+// the game even when it runs in a remote machine.
+func WithOSC52Clipboard() ConfigSetter {
+	return func(t *terminal) {
+		t.copy = func(s string) error {
+			t.render.string(fmt.Sprintf(osc52, base64.StdEncoding.EncodeToString([]byte(s))))
+			return nil
+		}
+	}
+}
+
+// WithSaver replaces where the game is saved, a nil saver disables saving.
+func WithSaver(s saver) ConfigSetter {
+	return func(t *terminal) {
+		t.saver = s
+	}
+}
+
+// WithPoster enables the post option in the post game menu.
+func WithPoster(p poster) ConfigSetter {
+	return func(t *terminal) {
+		t.poster = p
+	}
+}
+
+func New(w *wordle.Status, conf ...ConfigSetter) *terminal { //nolint: revive
+	t := &terminal{
+		reader:     os.Stdin,
+		writer:     os.Stdout,
+		tty:        newConsole(),
+		wordle:     w,
+		copy:       clipboard.WriteAll,
+		writeImage: writeImage,
+		saver:      status.Game(),
+		theme:      theme.Default,
+		colors:     theme.Detect(os.Getenv),
+		signals:    notifyShutdown,
+	}
+
+	for _, confSetter := range conf {
+		confSetter(t)
+	}
+
+	t.loop = newLoop(realClock{})
+	t.render = newRender(t.writer, t.loop)
+	t.render.race = t.racer != nil
+	t.round = newRound(w, t.render)
+	t.keyboard = newKeyboard(w, t.render)
+	t.render.add(t, t.round, t.keyboard)
+	t.applyTheme()
+	t.render.anim = t.animation
+
+	return t
+}
+
+// Start plays the game until it's finished, the player quits or the
+// process is asked to terminate. The game is saved in every case, even
+// when it panics.
+func (t *terminal) Start() (err error) {
+	shutdown, stop := t.signals()
+	defer stop()
+
+	if t.accessible {
+		return t.startAccessible(shutdown)
+	}
+
+	restore := func() error { return nil }
+	if t.tty != nil {
+		if restore, err = t.tty.MakeRaw(); err != nil {
+			return err
+		}
+	}
 
 	defer func() {
-		t.render.close()
-		restoreConsole()
-		if err := status.Game().Save(t.wordle); err != nil {
-			fmt.Println(err)
+		p := recover()
+		t.loop.stop()
+		t.render.flush()
+		t.render.string(disableMouse + disablePaste + fmt.Sprintf(moveTo, t.render.layout.lastRow()+1, 1) + "\r\n" + showCursor)
+		err = errors.Join(restore(), t.save())
+		if p != nil {
+			panic(p)
 		}
 	}()
 
+	t.render.string(hideCursor + enablePaste + enableMouse)
 	t.initialScreen()
-	t.game()
+	if t.wordle.Finish() {
+		t.finish()
+	}
+	t.run(shutdown)
+
+	return nil
 }
 
-func (t *terminal) game() {
-	for !t.wordle.Finish() {
-		buf, quit := t.read()
-		if quit {
+func (t *terminal) save() error {
+	if t.saver == nil {
+		return nil
+	}
+
+	return t.saver.SaveGame(t.wordle)
+}
+
+// autosave saves the game after every guess, so it's not lost when the
+// process dies before the game ends.
+func (t *terminal) autosave() {
+	if err := t.save(); err != nil {
+		t.render.err("Unable to save the game")
+	}
+}
+
+// key handles a key press. Keys pressed while a result is being revealed
+// are handled once it's displayed.
+func (t *terminal) key(e keyEvent) {
+	if e.kind == keyClick {
+		var ok bool
+		if e, ok = t.click(e); !ok {
 			return
 		}
+	}
+
+	switch {
+	case e.kind == keyCtrlC:
+		t.quit = true
+	case t.busy:
+		t.pending = append(t.pending, e)
+	case t.over:
+		t.postGame(e)
+	default:
+		t.processInput(e)
+	}
+}
 
-		t.processInput(buf[0])
+// replay handles the keys pressed while the game was busy.
+func (t *terminal) replay() {
+	for len(t.pending) > 0 && !t.busy && !t.quit {
+		e := t.pending[0]
+		t.pending = t.pending[1:]
+		t.key(e)
 	}
+}
 
-	t.render.string(fmt.Sprintf(italicFooter, t.finishingMsg()))
-	t.postGame()
+// finish shows the finishing message and the post game menu.
+func (t *terminal) finish() {
+	t.over = true
+	t.footer = t.finishingMsg()
+	t.postGameMenu()
 }
 
-func (t *terminal) postGame() {
-	t.render.string(postGameMenu)
+func (t *terminal) postGameMenu() {
+	t.menu = postGameMenu
+	if t.poster != nil {
+		t.menu = postGameMenuPost
+	}
+	t.render.refresh()
+}
 
-	for {
-		buf, quit := t.read()
-		if quit {
-			return
-		}
+func (t *terminal) postGame(e keyEvent) {
+	if t.menu == shareMenu {
+		t.share(e)
+		return
+	}
+	if e.kind != keyRune {
+		return
+	}
 
-		switch buf[0] {
-		case 's', 'S':
-			clipboard.WriteAll(t.wordle.Share()) //nolint: errcheck
-			t.render.err("Copied to Clipboard!")
-		case 'e', 'E':
-			return
+	switch e.r {
+	case 's', 'S':
+		t.menu = shareMenu
+		t.render.refresh()
+	case 'p', 'P':
+		if t.poster != nil {
+			t.post()
 		}
+	case 'e', 'E':
+		t.quit = true
 	}
 }
 
-func (t *terminal) processInput(b byte) {
-	t.keyboard.flash(b)
+// post sends the game to the leaderboard in the background, the UI keeps
+// responding until the server answers.
+func (t *terminal) post() {
+	if t.posting {
+		return
+	}
+	t.posting = true
 
-	switch b {
-	case backspace:
-		t.round.backspace()
-	case enter:
-		if t.round.index < 5 {
-			t.render.err("Not enough letters")
-			t.round.shake()
-			return
+	t.loop.background(func() func() {
+		err := t.poster.Post(t.wordle)
+		return func() {
+			t.posting = false
+			switch {
+			case err == nil:
+				t.render.err("Posted to the leaderboard!")
+			case errors.Is(err, leaderboard.ErrQueued):
+				t.render.err("Offline, will post later")
+			default:
+				t.render.err(err.Error())
+			}
 		}
+	})
+}
 
-		lastWord := strings.Join(t.round.status, "")
-		if err := t.wordle.Try(lastWord); err != nil {
-			t.render.err(err.Error())
-			t.round.shake()
-			return
-		}
+func (t *terminal) processInput(e keyEvent) {
+	t.keyboard.flash(e)
 
-		t.round.renderResult()
-		t.keyboard.print()
-	default:
-		c := strings.ToUpper(string(b))
-		if regexp.MustCompile(okRegex).MatchString(c) {
-			t.round.add(c)
+	switch e.kind {
+	case keyBackspace:
+		t.round.backspace()
+	case keyDelete:
+		t.round.delete()
+	case keyCtrlU, keyCtrlW:
+		t.round.deleteToStart()
+	case keyLeft:
+		t.round.move(t.round.cursor - 1)
+	case keyRight:
+		t.round.move(t.round.cursor + 1)
+	case keyHome:
+		t.round.move(0)
+	case keyEnd:
+		t.round.move(t.round.index)
+	case keyPaste:
+		t.round.paste(e.text)
+	case keyEnter:
+		t.enter()
+	case keyRune:
+		if c := unicode.ToUpper(e.r); c >= 'A' && c <= 'Z' {
+			t.round.add(string(c))
 		}
 	}
 }
 
-func (t *terminal) read() ([]byte, bool) {
-	buf := make([]byte, 1)
-	if _, err := t.reader.Read(buf); err != nil {
-		log.Fatalf("Error reading input: %v", err)
+// enter tries the word typed in the current row.
+func (t *terminal) enter() {
+	if t.round.index < 5 {
+		t.render.err("Not enough letters")
+		t.round.shake()
+		return
 	}
 
-	// Ctrl-C exits the game
-	if buf[0] == ctrlC {
-		return nil, true
+	lastWord := strings.Join(t.round.status, "")
+	if err := t.wordle.Try(lastWord); err != nil {
+		t.render.err(err.Error())
+		t.round.shake()
+		return
 	}
 
-	return buf, false
+	t.autosave()
+	t.reportRace()
+	t.busy = true
+	t.round.renderResult(func() {
+		t.busy = false
+		if t.wordle.Finish() {
+			t.finish()
+		}
+		t.replay()
+	})
 }
 
 func (t *terminal) initialScreen() {
-	t.render.string(title)
-	t.keyboard.print()
+	t.resize()
+	t.render.refresh()
+}
 
-	for i := range 6 {
-		t.round.print(i)
+// resize lays out the game for the current size of the tty, the default
+// size is kept when it's unknown.
+func (t *terminal) resize() {
+	if t.tty == nil {
+		return
 	}
+	if w, h, err := t.tty.Size(); err == nil && w > 0 && h > 0 {
+		t.render.resize(w, h)
+	}
+}
+
+func (t *terminal) draw(s *screen, l layout) {
+	s.print(l.titleRow, l.center(l.title), styleTitle, l.title)
+	s.print(l.footerRow, l.center(t.footer), styleFooter, t.footer)
+	menu := t.menuText(l)
+	s.print(l.menuRow, l.center(menu), styleDefault, menu)
+	t.drawRace(s, l)
 }
 
 func (t *terminal) finishingMsg() string {
@@ -164,18 +384,3 @@ func (t *terminal) finishingMsg() string {
 	}
 	return message
 }
-
-func startRawConsole() func() {
-	fmt.Print(hideCursor)
-	oldState, err := term.MakeRaw(int(os.Stdin.Fd()))
-	if err != nil {
-		log.Fatalf("Error setting terminal to raw mode: %v", err)
-	}
-
-	return func() {
-		if err := term.Restore(int(os.Stdin.Fd()), oldState); err != nil {
-			log.Fatalf("unable to retore the terminal original state: %v", err)
-		}
-		fmt.Print(showCursor)
-	}
-}
@@ -1,60 +1,92 @@
 package terminal
 
 import (
+	"bytes"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 
+	"github.com/Alvaroalonsobabbel/wordle/leaderboard"
 	"github.com/Alvaroalonsobabbel/wordle/wordle"
 	"github.com/stretchr/testify/assert"
 )
 
-type mockReader struct {
-	data []byte
-}
+func TestKey(t *testing.T) {
+	t.Run("ctrl-c exits the game", func(t *testing.T) {
+		terminal := newTestTerminal(io.Discard, strings.NewReader(""))
+		terminal.typeKeys("\x03")
+		assert.True(t, terminal.quit)
+	})
 
-func (m *mockReader) Read(p []byte) (int, error) {
-	n := copy(p, m.data)
-	m.data = m.data[n:]
-	return len(p), nil
-}
+	t.Run("letters are typed in the current row", func(t *testing.T) {
+		terminal := newTestTerminal(io.Discard, strings.NewReader(""))
+		terminal.typeKeys("a")
+		assert.False(t, terminal.quit)
+		assert.Equal(t, "A", terminal.round.status[0])
+	})
 
-func TestRead(t *testing.T) {
-	mockReader := &mockReader{}
-	terminal := newTestTerminal(io.Discard, mockReader)
+	t.Run("the current row is edited at the cursor", func(t *testing.T) {
+		terminal := newTestTerminal(io.Discard, strings.NewReader(""))
+		terminal.typeKeys("cxore\x1b[D\x1b[D\x1b[D\x7fh")
+		assert.Equal(t, []string{"C", "H", "O", "R", "E"}, terminal.round.status)
 
-	tests := []struct {
-		name     string
-		input    []byte
-		wantExit bool
-	}{
-		{"ctrl-c exits the game", []byte{ctrlC}, true},
-		{"'A' does not exits the game", []byte{'A'}, false},
-	}
+		terminal.typeKeys("\x1b[H\x1b[3~\x1b[F")
+		assert.Equal(t, []string{"H", "O", "R", "E", "_"}, terminal.round.status)
+		assert.Equal(t, 4, terminal.round.cursor)
 
-	for _, test := range tests {
-		t.Run(test.name, func(t *testing.T) {
-			mockReader.data = test.input
-			_, exit := terminal.read()
-			if test.wantExit {
-				assert.True(t, exit)
-			} else {
-				assert.False(t, exit)
-			}
-		})
-	}
+		terminal.typeKeys("\x02\x02\x17")
+		assert.Equal(t, []string{"R", "E", "_", "_", "_"}, terminal.round.status)
+		assert.Equal(t, 0, terminal.round.cursor)
+	})
+
+	t.Run("a pasted word can be submitted", func(t *testing.T) {
+		terminal := newTestTerminal(io.Discard, strings.NewReader(""))
+		terminal.typeKeys("\x1b[200~ Chore\n\x1b[201~\r")
+		terminal.loop.settle()
+		assert.Equal(t, 1, terminal.wordle.Round)
+		assert.True(t, terminal.over)
+	})
+
+	t.Run("keys pressed while revealing a result are handled once it's displayed", func(t *testing.T) {
+		terminal := newTestTerminal(io.Discard, strings.NewReader(""))
+		terminal.typeKeys("chair\rbo")
+		assert.True(t, terminal.busy)
+		assert.Equal(t, []keyEvent{runeKey('b'), runeKey('o')}, terminal.pending)
+
+		terminal.loop.settle()
+		assert.False(t, terminal.busy)
+		assert.Equal(t, []string{"B", "O", "_", "_", "_"}, terminal.round.status)
+	})
+
+	t.Run("finishing the game shows the post game menu", func(t *testing.T) {
+		terminal := newTestTerminal(io.Discard, strings.NewReader(""))
+		terminal.typeKeys("chore\re")
+		terminal.loop.settle()
+		assert.True(t, terminal.over)
+		assert.True(t, terminal.quit)
+	})
 }
 
 func newTestTerminal(w io.Writer, r io.Reader) *terminal { //nolint: revive
-	render := newRender(w)
+	loop := newLoop(&fakeClock{})
+	render := newRender(w, loop)
 	wordle := &wordle.Status{Wordle: "CHORE"}
-	return &terminal{
+	t := &terminal{
+		loop:     loop,
 		reader:   r,
 		render:   render,
 		wordle:   wordle,
 		keyboard: newKeyboard(wordle, render),
 		round:    newRound(wordle, render),
 	}
+	render.add(t, t.round, t.keyboard)
+
+	return t
 }
 
 func TestFinishingMessage(t *testing.T) {
@@ -72,3 +104,226 @@ func TestFinishingMessage(t *testing.T) {
 		})
 	}
 }
+
+type mockPoster struct {
+	posted *wordle.Status
+	err    error
+	// wait blocks posting until it's closed, when set.
+	wait  chan struct{}
+	calls atomic.Int32
+}
+
+func (m *mockPoster) Post(s *wordle.Status) error {
+	m.calls.Add(1)
+	if m.wait != nil {
+		<-m.wait
+	}
+	m.posted = s
+	return m.err
+}
+
+func TestPostGame(t *testing.T) {
+	tests := []struct {
+		name    string
+		poster  *mockPoster
+		wantErr string
+	}{
+		{"posted", &mockPoster{}, "Posted to the leaderboard!"},
+		{"queued", &mockPoster{err: leaderboard.ErrQueued}, "Offline, will post later"},
+		{"rejected", &mockPoster{err: errors.New("rejected")}, "rejected"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			terminal := newTestTerminal(buf, strings.NewReader(""))
+			WithPoster(test.poster)(terminal)
+
+			terminal.finish()
+			terminal.typeKeys("p")
+			terminal.loop.settle()
+			assert.Equal(t, terminal.wordle, test.poster.posted)
+			assert.Equal(t, postGameMenuPost, strings.TrimSpace(terminal.render.front.text(terminal.render.layout.menuRow)))
+			assert.Contains(t, buf.String(), test.wantErr)
+		})
+	}
+
+	t.Run("posting doesn't block the game", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		terminal := newTestTerminal(buf, strings.NewReader(""))
+		poster := &mockPoster{wait: make(chan struct{})}
+		WithPoster(poster)(terminal)
+
+		terminal.finish()
+		terminal.typeKeys("pp")
+		assert.True(t, terminal.posting)
+		terminal.typeKeys("s")
+		assert.Equal(t, shareMenu, terminal.menu, "keys are handled while posting")
+
+		close(poster.wait)
+		terminal.loop.settle()
+		assert.Equal(t, int32(1), poster.calls.Load(), "posting twice at once is ignored")
+		assert.Contains(t, buf.String(), "Posted to the leaderboard!")
+	})
+
+	t.Run("without poster post is not available", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		terminal := newTestTerminal(buf, strings.NewReader(""))
+
+		terminal.finish()
+		terminal.typeKeys("p")
+		assert.Equal(t, postGameMenu, strings.TrimSpace(terminal.render.front.text(terminal.render.layout.menuRow)))
+	})
+}
+
+func TestWithInputOutput(t *testing.T) {
+	t.Run("plays on the given input and output", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		wordle := &wordle.Status{Wordle: "HELLO"}
+		terminal := New(wordle, WithInput(strings.NewReader("\x03")), WithOutput(buf), WithTTY(nil), WithSaver(nil))
+		assert.NoError(t, terminal.Start())
+		assert.Contains(t, buf.String(), title)
+		assert.Contains(t, buf.String(), showCursor)
+	})
+
+	t.Run("closing the input exits the game", func(t *testing.T) {
+		saver := &mockSaver{}
+		terminal := New(&wordle.Status{Wordle: "HELLO"}, WithInput(strings.NewReader("ab")), WithOutput(io.Discard), WithTTY(nil), WithSaver(saver))
+		assert.NoError(t, terminal.Start())
+		assert.NotNil(t, saver.saved)
+	})
+
+	t.Run("leaves the cursor below the game", func(t *testing.T) {
+		for _, size := range [][2]int{{80, 24}, {120, 40}, {30, 12}, {20, 10}} {
+			buf := &bytes.Buffer{}
+			terminal := New(&wordle.Status{Wordle: "HELLO"}, WithInput(strings.NewReader("\x03")), WithOutput(buf), WithTTY(&mockTTY{width: size[0], height: size[1]}), WithSaver(nil))
+			assert.NoError(t, terminal.Start())
+
+			l := newLayout(size[0], size[1], false)
+			assert.True(t, strings.HasSuffix(buf.String(), fmt.Sprintf(moveTo, l.lastRow()+1, 1)+"\r\n"+showCursor), "%dx%d", size[0], size[1])
+			assert.Less(t, l.lastRow(), size[1])
+			if !l.tooSmall {
+				assert.GreaterOrEqual(t, l.lastRow(), l.menuRow)
+			}
+		}
+	})
+
+	t.Run("warns when the window is too small", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		terminal := New(&wordle.Status{Wordle: "HELLO"}, WithInput(strings.NewReader("\x03")), WithOutput(buf), WithTTY(&mockTTY{width: 20, height: 10}), WithSaver(nil))
+
+		assert.NoError(t, terminal.Start())
+		assert.True(t, terminal.tty.(*mockTTY).restored)
+		assert.Contains(t, buf.String(), tooSmall)
+	})
+}
+
+type mockTTY struct {
+	mu            sync.Mutex
+	width, height int
+	raw, restored bool
+	err           error
+	resized       chan struct{}
+}
+
+func (m *mockTTY) MakeRaw() (func() error, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.raw = true
+
+	return func() error {
+		m.restored = true
+		return nil
+	}, nil
+}
+
+func (m *mockTTY) Size() (int, int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.width, m.height, nil
+}
+
+func (m *mockTTY) setSize(width, height int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.width, m.height = width, height
+}
+
+func (m *mockTTY) Resized() <-chan struct{} {
+	return m.resized
+}
+
+func TestTTY(t *testing.T) {
+	t.Run("the tty is set to raw mode while playing", func(t *testing.T) {
+		tty := &mockTTY{width: 80, height: 24}
+		terminal := New(&wordle.Status{Wordle: "HELLO"}, WithInput(strings.NewReader("\x03")), WithOutput(io.Discard), WithTTY(tty), WithSaver(nil))
+
+		assert.NoError(t, terminal.Start())
+		assert.True(t, tty.raw)
+		assert.True(t, tty.restored)
+	})
+
+	t.Run("failing to set raw mode returns an error", func(t *testing.T) {
+		tty := &mockTTY{err: errors.New("not a terminal")}
+		terminal := New(&wordle.Status{Wordle: "HELLO"}, WithInput(strings.NewReader("\x03")), WithOutput(io.Discard), WithTTY(tty), WithSaver(nil))
+
+		assert.EqualError(t, terminal.Start(), "not a terminal")
+	})
+
+	t.Run("saving errors are returned", func(t *testing.T) {
+		terminal := New(&wordle.Status{Wordle: "HELLO"}, WithInput(strings.NewReader("\x03")), WithOutput(io.Discard), WithTTY(nil), WithSaver(&mockSaver{err: errors.New("disk full")}))
+
+		assert.EqualError(t, terminal.Start(), "disk full")
+	})
+}
+
+type mockSaver struct {
+	saved *wordle.Status
+	saves int
+	err   error
+}
+
+func (m *mockSaver) SaveGame(s *wordle.Status) error {
+	m.saved = s
+	m.saves++
+	return m.err
+}
+
+func TestAutosave(t *testing.T) {
+	t.Run("the game is saved after every guess", func(t *testing.T) {
+		saver := &mockSaver{}
+		terminal := newTestTerminal(io.Discard, strings.NewReader(""))
+		WithSaver(saver)(terminal)
+
+		terminal.typeKeys("abc\rxxxxx\r")
+		assert.Equal(t, 0, saver.saves, "failed guesses are not saved")
+		terminal.typeKeys("\x15chair\r")
+		terminal.loop.settle()
+		terminal.typeKeys("chore\r")
+		terminal.loop.settle()
+		assert.Equal(t, 2, saver.saves)
+	})
+
+	t.Run("saving errors are shown", func(t *testing.T) {
+		terminal := newTestTerminal(io.Discard, strings.NewReader(""))
+		WithSaver(&mockSaver{err: errors.New("disk full")})(terminal)
+
+		terminal.typeKeys("chair\r")
+		assert.Contains(t, terminal.render.errQ, "Unable to save the game")
+		assert.Equal(t, 1, terminal.wordle.Round)
+	})
+}
+
+func TestWithOSC52Clipboard(t *testing.T) {
+	buf := &bytes.Buffer{}
+	wordle := &wordle.Status{Wordle: "HELLO"}
+	assert.NoError(t, wordle.Try("HELLO"))
+	terminal := New(wordle, WithInput(strings.NewReader("sne")), WithOutput(buf), WithTTY(nil), WithOSC52Clipboard(), WithSaver(nil))
+
+	assert.NoError(t, terminal.Start())
+	assert.Contains(t, buf.String(), "\033]52;c;"+base64.StdEncoding.EncodeToString([]byte(wordle.Share()))+"\a")
+	assert.Contains(t, buf.String(), "Copied to Clipboard!")
+}
@@ -0,0 +1,53 @@
+package terminal
+
+import (
+	"github.com/Alvaroalonsobabbel/wordle/theme"
+	"github.com/Alvaroalonsobabbel/wordle/wordle"
+)
+
+// WithTheme displays the game with th. Themes sharing results in high
+// contrast switch the shared squares to orange and blue.
+func WithTheme(th theme.Theme) ConfigSetter {
+	return func(t *terminal) {
+		t.theme = th
+	}
+}
+
+// WithColors overrides the colors the terminal is detected to support.
+func WithColors(p theme.Profile) ConfigSetter {
+	return func(t *terminal) {
+		t.colors = p
+	}
+}
+
+func (t *terminal) applyTheme() {
+	// Without colors results are told apart by their symbols.
+	if t.colors == theme.NoColor && !t.theme.Symbols() {
+		highContrast := t.theme.HighContrast
+		t.theme = theme.Monochrome
+		t.theme.HighContrast = highContrast
+	}
+	if t.theme.HighContrast {
+		wordle.WithHighContrast()(t.wordle)
+	}
+	t.render.setTheme(t.theme, t.colors)
+}
+
+// styles returns the escape sequence and marks of every style.
+func styles(th theme.Theme, p theme.Profile) (map[style]string, map[style]string) {
+	sgr, marks := map[style]string{}, map[style]string{}
+	for st, s := range map[style]theme.Style{
+		styleTitle:   th.Title,
+		styleFooter:  th.Footer,
+		styleCorrect: th.Correct,
+		stylePresent: th.Present,
+		styleAbsent:  th.Absent,
+		styleFlash:   th.Flash,
+		styleError:   th.Error,
+		styleCursor:  th.Cursor,
+	} {
+		sgr[st], marks[st] = s.SGR(p), s.Marks
+	}
+
+	return sgr, marks
+}
@@ -0,0 +1,62 @@
+package terminal
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/Alvaroalonsobabbel/wordle/theme"
+	"github.com/Alvaroalonsobabbel/wordle/wordle"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTheme(t *testing.T) {
+	newGame := func() *wordle.Status {
+		w := &wordle.Status{Wordle: "HELLO"}
+		assert.NoError(t, w.Try("OLLIE"))
+		return w
+	}
+
+	t.Run("results are displayed with the theme colors", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		terminal := New(newGame(), WithOutput(buf), WithTTY(nil), WithTheme(theme.HighContrast), WithColors(theme.TrueColor))
+		terminal.render.refresh()
+
+		assert.Contains(t, buf.String(), "\x1b[7;38;2;245;121;58m L ")
+	})
+
+	t.Run("symbols tell results apart", func(t *testing.T) {
+		terminal := New(newGame(), WithOutput(io.Discard), WithTTY(nil), WithTheme(theme.Monochrome))
+		terminal.render.refresh()
+
+		l := terminal.render.layout
+		assert.Equal(t, "(O)(L)[L] I (E)", strings.TrimSpace(terminal.render.front.text(l.boardRow)))
+		assert.Contains(t, terminal.render.front.text(l.keyboardRow), "(O)")
+	})
+
+	t.Run("without colors the monochrome theme is used", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		terminal := New(newGame(), WithOutput(buf), WithTTY(nil), WithColors(theme.NoColor))
+		terminal.render.refresh()
+
+		assert.Equal(t, theme.Monochrome, terminal.theme)
+		assert.NotContains(t, buf.String(), "\x1b[7;32m")
+	})
+
+	t.Run("high contrast themes share orange and blue squares", func(t *testing.T) {
+		w := newGame()
+		New(w, WithOutput(io.Discard), WithTTY(nil), WithTheme(theme.HighContrast))
+
+		assert.Contains(t, w.Share(), "🟦🟦🟧⬜️🟦")
+	})
+
+	t.Run("without colors high contrast themes still share orange and blue squares", func(t *testing.T) {
+		w := newGame()
+		terminal := New(w, WithOutput(io.Discard), WithTTY(nil), WithTheme(theme.HighContrast), WithColors(theme.NoColor))
+
+		assert.True(t, terminal.theme.Symbols())
+		assert.True(t, terminal.theme.HighContrast)
+		assert.Contains(t, w.Share(), "🟦🟦🟧⬜️🟦")
+	})
+}
@@ -0,0 +1,68 @@
+package terminal
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"golang.org/x/term"
+)
+
+// TTY controls the terminal the game is displayed in.
+type TTY interface {
+	// MakeRaw puts the terminal in raw mode so every key press is
+	// delivered as it's typed. The returned func restores it.
+	MakeRaw() (restore func() error, err error)
+	// Size returns the width and height of the terminal.
+	Size() (width, height int, err error)
+	// Resized receives a value every time the terminal changes its size.
+	Resized() <-chan struct{}
+}
+
+// console is the TTY of the process standard input.
+type console struct {
+	fd      int
+	once    sync.Once
+	resized chan struct{}
+}
+
+func newConsole() *console {
+	return &console{fd: int(os.Stdin.Fd()), resized: make(chan struct{}, 1)}
+}
+
+func (c *console) MakeRaw() (func() error, error) {
+	oldState, err := term.MakeRaw(c.fd)
+	if err != nil {
+		return nil, fmt.Errorf("error setting terminal to raw mode: %v", err)
+	}
+
+	return func() error {
+		if err := term.Restore(c.fd, oldState); err != nil {
+			return fmt.Errorf("unable to restore the terminal original state: %v", err)
+		}
+		return nil
+	}, nil
+}
+
+func (c *console) Size() (int, int, error) {
+	return term.GetSize(c.fd)
+}
+
+// Resized starts listening to the window size changes of the process
+// controlling terminal the first time it's called.
+func (c *console) Resized() <-chan struct{} {
+	c.once.Do(func() {
+		sig := make(chan os.Signal, 1)
+		notifyResize(sig)
+		go func() {
+			for range sig {
+				select {
+				case c.resized <- struct{}{}:
+				default:
+				}
+			}
+		}()
+	})
+
+	return c.resized
+}
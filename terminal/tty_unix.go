@@ -0,0 +1,16 @@
+//go:build !windows
+
+package terminal
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+func notifyResize(c chan<- os.Signal) {
+	signal.Notify(c, syscall.SIGWINCH)
+}
+
+// shutdownSignals are sent when the process is killed or its terminal is closed.
+var shutdownSignals = []os.Signal{os.Interrupt, syscall.SIGTERM, syscall.SIGHUP}
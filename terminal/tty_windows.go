@@ -0,0 +1,15 @@
+//go:build windows
+
+package terminal
+
+import (
+	"os"
+	"syscall"
+)
+
+// Windows has no signal for window size changes, the size read when the
+// game starts is kept.
+func notifyResize(chan<- os.Signal) {}
+
+// shutdownSignals are sent when the process is interrupted or its console is closed.
+var shutdownSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
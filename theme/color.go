@@ -0,0 +1,167 @@
+package theme
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Profile is the range of colors a terminal can display.
+type Profile int
+
+const (
+	NoColor Profile = iota
+	ANSI
+	ANSI256
+	TrueColor
+)
+
+type rgb struct{ r, g, b int }
+
+var (
+	basicNames = []string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+	// basicRGB is the usual xterm value of the 16 basic colors.
+	basicRGB = []rgb{
+		{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0}, {0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+		{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0}, {92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+	}
+	cubeSteps = []int{0, 95, 135, 175, 215, 255}
+)
+
+// Detect tells the color profile of the terminal from the environment,
+// following the NO_COLOR convention, see https://no-color.org.
+func Detect(getenv func(string) string) Profile {
+	term := getenv("TERM")
+	switch {
+	case getenv("NO_COLOR") != "", term == "dumb":
+		return NoColor
+	case getenv("COLORTERM") == "truecolor", getenv("COLORTERM") == "24bit":
+		return TrueColor
+	case strings.Contains(term, "256color"):
+		return ANSI256
+	default:
+		return ANSI
+	}
+}
+
+// SGR returns the escape sequence that sets the style in a terminal with profile p.
+func (s Style) SGR(p Profile) string {
+	var params []string
+	for _, a := range []struct {
+		on    bool
+		param string
+	}{{s.Bold, "1"}, {s.Dim, "2"}, {s.Italic, "3"}, {s.Underline, "4"}, {s.Reverse, "7"}} {
+		if a.on {
+			params = append(params, a.param)
+		}
+	}
+	if fg := color(s.Foreground, p, false); fg != "" {
+		params = append(params, fg)
+	}
+	if bg := color(s.Background, p, true); bg != "" {
+		params = append(params, bg)
+	}
+	if len(params) == 0 {
+		return ""
+	}
+
+	return "\x1b[" + strings.Join(params, ";") + "m"
+}
+
+// color returns the SGR parameter of the color c, empty when the color is invalid or can't be displayed.
+func color(c string, p Profile, background bool) string {
+	if c == "" || p == NoColor {
+		return ""
+	}
+
+	basic := func(i int) string {
+		base := 30
+		if i > 7 {
+			base, i = 90, i-8
+		}
+		if background {
+			base += 10
+		}
+		return strconv.Itoa(base + i)
+	}
+	extended := "38"
+	if background {
+		extended = "48"
+	}
+
+	if i := basicIndex(c); i >= 0 {
+		return basic(i)
+	}
+
+	var v rgb
+	if n, err := strconv.Atoi(c); err == nil && n >= 0 && n < 256 {
+		if p >= ANSI256 {
+			return fmt.Sprintf("%s;5;%d", extended, n)
+		}
+		v = paletteRGB(n)
+	} else if _, err := fmt.Sscanf(c, "#%02x%02x%02x", &v.r, &v.g, &v.b); err != nil || len(c) != 7 {
+		return ""
+	}
+
+	switch p {
+	case TrueColor:
+		return fmt.Sprintf("%s;2;%d;%d;%d", extended, v.r, v.g, v.b)
+	case ANSI256:
+		return fmt.Sprintf("%s;5;%d", extended, cubeIndex(v))
+	default:
+		return basic(nearest(v, basicRGB))
+	}
+}
+
+func basicIndex(name string) int {
+	bright := strings.HasPrefix(name, "bright-")
+	for i, n := range basicNames {
+		if strings.TrimPrefix(name, "bright-") == n {
+			if bright {
+				return i + 8
+			}
+			return i
+		}
+	}
+
+	return -1
+}
+
+// paletteRGB returns the value of the n color of the 256 palette.
+func paletteRGB(n int) rgb {
+	switch {
+	case n < 16:
+		return basicRGB[n]
+	case n < 232:
+		n -= 16
+		return rgb{cubeSteps[n/36], cubeSteps[n/6%6], cubeSteps[n%6]}
+	default:
+		g := 8 + (n-232)*10
+		return rgb{g, g, g}
+	}
+}
+
+// cubeIndex returns the closest color of the 256 palette 6x6x6 cube.
+func cubeIndex(v rgb) int {
+	step := func(c int) int {
+		steps := make([]rgb, len(cubeSteps))
+		for i, s := range cubeSteps {
+			steps[i] = rgb{s, s, s}
+		}
+		return nearest(rgb{c, c, c}, steps)
+	}
+
+	return 16 + 36*step(v.r) + 6*step(v.g) + step(v.b)
+}
+
+func nearest(v rgb, palette []rgb) int {
+	best, dist := 0, -1
+	for i, c := range palette {
+		d := (v.r-c.r)*(v.r-c.r) + (v.g-c.g)*(v.g-c.g) + (v.b-c.b)*(v.b-c.b)
+		if dist < 0 || d < dist {
+			best, dist = i, d
+		}
+	}
+
+	return best
+}
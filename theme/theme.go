@@ -0,0 +1,161 @@
+// Package theme holds the colors and symbols the game is displayed with.
+package theme
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var ErrUnknownTheme = errors.New("unknown theme")
+
+// Style is what a part of the game looks like. Colors are either one of
+// the basic terminal color names (i.e. "green", "bright-black"), a 256
+// palette index (i.e. "208") or a "#rrggbb" hex value, and they're
+// downgraded to what the terminal supports.
+type Style struct {
+	Foreground string `json:"fg,omitempty"`
+	Background string `json:"bg,omitempty"`
+	Bold       bool   `json:"bold,omitempty"`
+	Dim        bool   `json:"dim,omitempty"`
+	Italic     bool   `json:"italic,omitempty"`
+	Underline  bool   `json:"underline,omitempty"`
+	Reverse    bool   `json:"reverse,omitempty"`
+	// Marks are the two characters surrounding the letter of a tile,
+	// which tell results apart without relying on color.
+	Marks string `json:"marks,omitempty"`
+}
+
+type Theme struct {
+	// Base is the built-in theme a user theme starts from, the default one when empty.
+	Base    string `json:"base,omitempty"`
+	Title   Style  `json:"title"`
+	Footer  Style  `json:"footer"`
+	Correct Style  `json:"correct"`
+	Present Style  `json:"present"`
+	Absent  Style  `json:"absent"`
+	Flash   Style  `json:"flash"`
+	Error   Style  `json:"error"`
+	// Cursor is the letter the cursor is on after moving it within a row.
+	Cursor Style `json:"cursor"`
+	// HighContrast shares the results with orange and blue squares.
+	HighContrast bool `json:"high_contrast,omitempty"`
+}
+
+var (
+	Default = Theme{
+		Title:   Style{Bold: true},
+		Footer:  Style{Italic: true},
+		Correct: Style{Foreground: "green", Reverse: true},
+		Present: Style{Foreground: "yellow", Reverse: true},
+		Absent:  Style{Foreground: "bright-black", Reverse: true},
+		Flash:   Style{Foreground: "black", Background: "white"},
+		Error:   Style{Foreground: "black", Background: "white", Italic: true},
+		Cursor:  Style{Reverse: true},
+	}
+
+	// HighContrast uses the orange and blue of the official colorblind mode.
+	HighContrast = Theme{
+		Title:        Style{Bold: true},
+		Footer:       Style{Italic: true},
+		Correct:      Style{Foreground: "#f5793a", Reverse: true},
+		Present:      Style{Foreground: "#85c0f9", Reverse: true},
+		Absent:       Style{Foreground: "bright-black", Reverse: true},
+		Flash:        Style{Foreground: "black", Background: "white"},
+		Error:        Style{Foreground: "black", Background: "white", Italic: true},
+		Cursor:       Style{Reverse: true},
+		HighContrast: true,
+	}
+
+	// Monochrome tells results apart with symbols and text attributes only.
+	Monochrome = Theme{
+		Title:   Style{Bold: true},
+		Footer:  Style{Italic: true},
+		Correct: Style{Bold: true, Reverse: true, Marks: "[]"},
+		Present: Style{Underline: true, Marks: "()"},
+		Absent:  Style{Dim: true},
+		Flash:   Style{Reverse: true},
+		Error:   Style{Italic: true, Reverse: true},
+		Cursor:  Style{Reverse: true},
+	}
+
+	// Light is meant for terminals with a light background.
+	Light = Theme{
+		Title:   Style{Bold: true},
+		Footer:  Style{Italic: true},
+		Correct: Style{Foreground: "white", Background: "#6aaa64", Bold: true},
+		Present: Style{Foreground: "white", Background: "#c9b458", Bold: true},
+		Absent:  Style{Foreground: "white", Background: "#787c7e", Bold: true},
+		Flash:   Style{Foreground: "white", Background: "black"},
+		Error:   Style{Foreground: "white", Background: "black", Italic: true},
+		Cursor:  Style{Reverse: true},
+	}
+
+	builtIn = map[string]Theme{
+		"default":       Default,
+		"high-contrast": HighContrast,
+		"monochrome":    Monochrome,
+		"light":         Light,
+	}
+)
+
+// Names returns the names of the built-in themes.
+func Names() []string {
+	var names []string
+	for n := range builtIn {
+		names = append(names, n)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// Get returns the theme called name, user themes take precedence over
+// the built-in ones. The default theme is returned for an empty name.
+func Get(name string, user map[string]Theme) (Theme, error) {
+	if name == "" {
+		return Default, nil
+	}
+	if t, ok := user[name]; ok {
+		base, ok := builtIn[t.Base]
+		if t.Base == "" {
+			base, ok = Default, true
+		}
+		if !ok {
+			return Theme{}, fmt.Errorf("%w %q, the base of %q", ErrUnknownTheme, t.Base, name)
+		}
+		return t.over(base), nil
+	}
+	if t, ok := builtIn[name]; ok {
+		return t, nil
+	}
+
+	return Theme{}, fmt.Errorf("%w %q, choose one of %s", ErrUnknownTheme, name, strings.Join(Names(), ", "))
+}
+
+// over replaces the styles of base with the ones set in t.
+func (t Theme) over(base Theme) Theme {
+	for _, s := range []struct{ dst, src *Style }{
+		{&base.Title, &t.Title},
+		{&base.Footer, &t.Footer},
+		{&base.Correct, &t.Correct},
+		{&base.Present, &t.Present},
+		{&base.Absent, &t.Absent},
+		{&base.Flash, &t.Flash},
+		{&base.Error, &t.Error},
+		{&base.Cursor, &t.Cursor},
+	} {
+		if *s.src != (Style{}) {
+			*s.dst = *s.src
+		}
+	}
+	base.HighContrast = base.HighContrast || t.HighContrast
+
+	return base
+}
+
+// Symbols reports whether results can be told apart without color.
+func (t Theme) Symbols() bool {
+	return t.Correct.Marks != "" || t.Present.Marks != ""
+}
@@ -0,0 +1,101 @@
+package theme
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGet(t *testing.T) {
+	user := map[string]Theme{
+		"mine":    {Correct: Style{Foreground: "#00ff00"}},
+		"orange":  {Base: "high-contrast", Absent: Style{Dim: true}},
+		"broken":  {Base: "neon"},
+		"default": {Title: Style{Underline: true}},
+	}
+
+	tests := []struct {
+		name    string
+		want    func() Theme
+		wantErr error
+	}{
+		{name: "", want: func() Theme { return Default }},
+		{name: "monochrome", want: func() Theme { return Monochrome }},
+		{name: "mine", want: func() Theme {
+			th := Default
+			th.Correct = Style{Foreground: "#00ff00"}
+			return th
+		}},
+		{name: "orange", want: func() Theme {
+			th := HighContrast
+			th.Absent = Style{Dim: true}
+			return th
+		}},
+		{name: "default", want: func() Theme {
+			th := Default
+			th.Title = Style{Underline: true}
+			return th
+		}},
+		{name: "broken", wantErr: ErrUnknownTheme},
+		{name: "neon", wantErr: ErrUnknownTheme},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := Get(test.name, user)
+			if test.wantErr != nil {
+				assert.ErrorIs(t, err, test.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want(), got)
+		})
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want Profile
+	}{
+		{"no color", map[string]string{"NO_COLOR": "1", "COLORTERM": "truecolor"}, NoColor},
+		{"dumb terminal", map[string]string{"TERM": "dumb"}, NoColor},
+		{"truecolor", map[string]string{"TERM": "xterm-256color", "COLORTERM": "truecolor"}, TrueColor},
+		{"24bit", map[string]string{"COLORTERM": "24bit"}, TrueColor},
+		{"256 colors", map[string]string{"TERM": "xterm-256color"}, ANSI256},
+		{"basic", map[string]string{"TERM": "xterm"}, ANSI},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, Detect(func(k string) string { return test.env[k] }))
+		})
+	}
+}
+
+func TestSGR(t *testing.T) {
+	tests := []struct {
+		name    string
+		style   Style
+		profile Profile
+		want    string
+	}{
+		{"attributes", Style{Bold: true, Dim: true, Italic: true, Underline: true, Reverse: true}, ANSI, "\x1b[1;2;3;4;7m"},
+		{"empty", Style{}, TrueColor, ""},
+		{"basic colors", Style{Foreground: "green", Background: "bright-black"}, TrueColor, "\x1b[32;100m"},
+		{"no color keeps the attributes", Style{Foreground: "green", Reverse: true}, NoColor, "\x1b[7m"},
+		{"truecolor", Style{Foreground: "#f5793a"}, TrueColor, "\x1b[38;2;245;121;58m"},
+		{"hex in 256 colors", Style{Background: "#f5793a"}, ANSI256, "\x1b[48;5;209m"},
+		{"hex in basic colors", Style{Foreground: "#f5793a"}, ANSI, "\x1b[33m"},
+		{"palette index", Style{Foreground: "208"}, ANSI256, "\x1b[38;5;208m"},
+		{"palette index in basic colors", Style{Foreground: "21"}, ANSI, "\x1b[34m"},
+		{"invalid colors are ignored", Style{Foreground: "#f57", Background: "sky", Bold: true}, TrueColor, "\x1b[1m"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, test.style.SGR(test.profile))
+		})
+	}
+}
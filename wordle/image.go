@@ -0,0 +1,76 @@
+package wordle
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+)
+
+const (
+	tileSize = 60
+	tileGap  = 6
+	boardPad = 12
+)
+
+// Colors of the tiles in the NYT game.
+var (
+	backgroundColor  = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	absentColor      = color.RGBA{0x78, 0x7c, 0x7e, 0xff}
+	correctColor     = color.RGBA{0x6a, 0xaa, 0x64, 0xff}
+	presentColor     = color.RGBA{0xc9, 0xb4, 0x58, 0xff}
+	highCorrectColor = color.RGBA{0xf5, 0x79, 0x3a, 0xff}
+	highPresentColor = color.RGBA{0x85, 0xc0, 0xf9, 0xff}
+	emptyBorderColor = color.RGBA{0xd3, 0xd6, 0xda, 0xff}
+
+	// Colors of the dark mode.
+	darkBackgroundColor = color.RGBA{0x12, 0x12, 0x13, 0xff}
+	darkAbsentColor     = color.RGBA{0x3a, 0x3a, 0x3c, 0xff}
+)
+
+// ShareImage is a PNG image of the board with the colors of the results
+// but not the letters, so it can be shared without spoiling the game. It's
+// drawn on a dark background when sharing dark squares.
+func (s *Status) ShareImage() ([]byte, error) {
+	correct, present := correctColor, presentColor
+	if s.highContrast {
+		correct, present = highCorrectColor, highPresentColor
+	}
+	background, absent, emptyBorder := backgroundColor, absentColor, emptyBorderColor
+	if s.darkSquares {
+		// Empty squares have the border of the absent ones.
+		background, absent, emptyBorder = darkBackgroundColor, darkAbsentColor, darkAbsentColor
+	}
+	colors := map[int]color.Color{Absent: absent, Correct: correct, Present: present}
+
+	side := func(n int) int { return 2*boardPad + n*tileSize + (n-1)*tileGap }
+	img := image.NewRGBA(image.Rect(0, 0, side(wordLength), side(maxAttempts)))
+	draw.Draw(img, img.Bounds(), image.NewUniform(background), image.Point{}, draw.Src)
+
+	for row := range maxAttempts {
+		var res []int
+		if row < len(s.Results) {
+			res = results(s.Results[row])
+		}
+		for col := range wordLength {
+			x, y := boardPad+col*(tileSize+tileGap), boardPad+row*(tileSize+tileGap)
+			tile := image.Rect(x, y, x+tileSize, y+tileSize)
+			if col < len(res) {
+				draw.Draw(img, tile, image.NewUniform(colors[res[col]]), image.Point{}, draw.Src)
+				continue
+			}
+			// Rows not played are empty squares.
+			draw.Draw(img, tile, image.NewUniform(emptyBorder), image.Point{}, draw.Src)
+			draw.Draw(img, tile.Inset(2), image.NewUniform(background), image.Point{}, draw.Src)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("error encoding image: %v", err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,145 @@
+package wordle
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	tokenLength = 8
+	tokenPrefix = "#"
+
+	variationSelector = "\ufe0f"
+)
+
+var (
+	ErrTampered     = errors.New("game has been tampered with")
+	ErrNotFinished  = errors.New("there is no finished game to verify against")
+	ErrShareInvalid = errors.New("result does not match the saved game")
+	ErrTokenInvalid = errors.New("verification token does not match the saved game")
+	ErrUnverifiable = errors.New("game was saved before results were signed and can't be verified")
+
+	// colorModes reads the squares of every color mode as the regular ones.
+	colorModes = strings.NewReplacer(orangeSquare, correctSquare, blueSquare, presentSquare, blackSquare, whiteSquare)
+)
+
+// WithShareToken makes Share append a short verification token derived
+// from the game's HMAC with the given key.
+func WithShareToken(key []byte) ConfigSetter {
+	return func(s *Status) {
+		s.shareKey = key
+	}
+}
+
+// Seal stores in the status the HMAC of its guesses and outcome. The
+// answer of a game in progress is hashed again when key is a new one.
+func (s *Status) Seal(key []byte) {
+	s.rekey(key)
+	s.key = key
+	s.MAC = s.mac(key)
+}
+
+// Verify checks the status HMAC against its guesses and outcome. Games
+// saved before integrity checks were introduced have no HMAC and the
+// answer in plain text. They're accepted but marked as Unverified, so
+// their results are never vouched for even after they're sealed.
+func (s *Status) Verify(key []byte) error {
+	s.key = key
+	if s.MAC == "" {
+		if s.Wordle == "" || s.wordleHash != "" {
+			return ErrTampered
+		}
+		s.Unverified = true
+		return nil
+	}
+	if !hmac.Equal([]byte(s.MAC), []byte(s.mac(key))) {
+		return ErrTampered
+	}
+
+	return nil
+}
+
+// Token is the short verification token appended to shared results.
+func (s *Status) Token(key []byte) string {
+	return s.mac(key)[:tokenLength]
+}
+
+// VerifyShare checks that a pasted result in any of the share formats,
+// including its verification token, matches this finished game.
+func (s *Status) VerifyShare(text string, key []byte) error {
+	if !s.Finish() {
+		return ErrNotFinished
+	}
+	if err := s.Verify(key); err != nil {
+		return err
+	}
+	if s.Unverified {
+		return ErrUnverifiable
+	}
+
+	got := normalizeShare(text)
+	var token string
+	if len(got) > 0 && strings.HasPrefix(got[len(got)-1], tokenPrefix) {
+		token = strings.TrimPrefix(got[len(got)-1], tokenPrefix)
+		got = got[:len(got)-1]
+	}
+
+	matches := false
+	for _, f := range []ShareFormat{ShareNYT, ShareSpoiler, ShareASCII} {
+		want := normalizeShare(s.ShareAs(f))
+		if want[len(want)-1] == tokenPrefix+s.Token(key) {
+			want = want[:len(want)-1]
+		}
+		if strings.Join(got, newLine) == strings.Join(want, newLine) {
+			matches = true
+			break
+		}
+	}
+	if !matches {
+		return ErrShareInvalid
+	}
+	if !hmac.Equal([]byte(token), []byte(s.Token(key))) {
+		return ErrTokenInvalid
+	}
+
+	return nil
+}
+
+func (s *Status) mac(key []byte) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d|%t|%d|%t|%s|%s|%s|", s.PuzzleNumber, s.HardMode, s.Round, s.Unverified, string(s.Discovered[:]), string(s.Hints), string(s.Used))
+	for _, res := range s.Results {
+		for _, stat := range res {
+			for k, v := range stat {
+				b.WriteRune(k)
+				b.WriteString(strconv.Itoa(v))
+			}
+		}
+		b.WriteString("|")
+	}
+
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(b.String()))
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// normalizeShare strips the differences chat apps usually introduce when
+// pasting a result: surrounding whitespace, blank lines and emoji
+// variation selectors. High contrast and dark squares are read as the
+// regular ones.
+func normalizeShare(text string) []string {
+	var lines []string
+	for _, l := range strings.Split(strings.ReplaceAll(text, variationSelector, ""), newLine) {
+		if l = strings.TrimSpace(l); l != "" {
+			lines = append(lines, colorModes.Replace(l))
+		}
+	}
+
+	return lines
+}
@@ -0,0 +1,193 @@
+package wordle
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testKey = []byte("0123456789abcdef0123456789abcdef")
+
+func TestSealAndVerify(t *testing.T) {
+	t.Run("a sealed game verifies", func(t *testing.T) {
+		wordle := &Status{Wordle: "HELLO"}
+		assert.NoError(t, wordle.Try("CELLO"))
+		wordle.Seal(testKey)
+		assert.NoError(t, wordle.Verify(testKey))
+	})
+
+	t.Run("a game saved before signing verifies but is unverified", func(t *testing.T) {
+		wordle := &Status{Wordle: "HELLO"}
+		assert.NoError(t, wordle.Verify(testKey))
+		assert.True(t, wordle.Unverified)
+	})
+
+	t.Run("a game in progress without MAC doesn't verify", func(t *testing.T) {
+		wordle := &Status{}
+		assert.NoError(t, wordle.UnmarshalJSON([]byte(`{"round":1,"wordle_hash":"abc"}`)))
+		assert.ErrorIs(t, wordle.Verify(testKey), ErrTampered)
+	})
+
+	t.Run("a sealed game with a different key doesn't verify", func(t *testing.T) {
+		wordle := &Status{Wordle: "HELLO"}
+		wordle.Seal(testKey)
+		assert.ErrorIs(t, wordle.Verify([]byte("other key")), ErrTampered)
+	})
+
+	t.Run("changing the results or round makes the game fail to verify", func(t *testing.T) {
+		wordle := &Status{Wordle: "HELLO"}
+		assert.NoError(t, wordle.Try("CELLO"))
+		assert.NoError(t, wordle.Try("HELLO"))
+		wordle.Seal(testKey)
+
+		wordle.Round = 1
+		assert.ErrorIs(t, wordle.Verify(testKey), ErrTampered)
+
+		wordle.Round = 2
+		wordle.Results[0][0]['C'] = Correct
+		assert.ErrorIs(t, wordle.Verify(testKey), ErrTampered)
+	})
+
+	lost := func() *Status {
+		wordle := &Status{Wordle: "HELLO"}
+		for range 6 {
+			assert.NoError(t, wordle.Try("CELLO"))
+		}
+		wordle.Seal(testKey)
+		return wordle
+	}
+	for field, tamper := range map[string]func(*Status){
+		"discovered": func(s *Status) { s.Discovered = [5]rune{'H', 'E', 'L', 'L', 'O'} },
+		"hints":      func(s *Status) { s.Hints = append(s.Hints, 'H') },
+		"used":       func(s *Status) { s.Used = s.Used[1:] },
+		"unverified": func(s *Status) { s.Unverified = true },
+		"hard mode":  func(s *Status) { s.HardMode = true },
+		"puzzle":     func(s *Status) { s.PuzzleNumber = 1 },
+	} {
+		t.Run("changing the "+field+" makes the game fail to verify", func(t *testing.T) {
+			wordle := lost()
+			tamper(wordle)
+			assert.ErrorIs(t, wordle.Verify(testKey), ErrTampered)
+		})
+	}
+}
+
+func TestShareToken(t *testing.T) {
+	wordle := &Status{Wordle: "HELLO"}
+	WithShareToken(testKey)(wordle)
+	assert.NoError(t, wordle.Try("CELLO"))
+	assert.NoError(t, wordle.Try("HELLO"))
+
+	lines := strings.Split(wordle.Share(), newLine)
+	assert.Len(t, lines, 5)
+	assert.Equal(t, tokenPrefix+wordle.Token(testKey), lines[4])
+	assert.Len(t, wordle.Token(testKey), tokenLength)
+}
+
+func TestVerifyShare(t *testing.T) {
+	newFinishedGame := func() *Status {
+		wordle := &Status{Wordle: "HELLO", PuzzleNumber: 1234}
+		assert.NoError(t, wordle.Try("CELLO"))
+		assert.NoError(t, wordle.Try("HELLO"))
+		wordle.Seal(testKey)
+		return wordle
+	}
+	token := tokenPrefix + newFinishedGame().Token(testKey)
+
+	tests := []struct {
+		name    string
+		paste   string
+		wantErr error
+	}{
+		{
+			name:  "exact paste",
+			paste: "Wordle 1,234 2/6\n⬜️🟩🟩🟩🟩\n🟩🟩🟩🟩🟩\n" + token,
+		},
+		{
+			name:  "paste with extra whitespace and without variation selectors",
+			paste: "\n  Wordle 1,234 2/6 \n\n⬜🟩🟩🟩🟩\n🟩🟩🟩🟩🟩\n\n " + token + "\n",
+		},
+		{
+			name:  "high contrast paste",
+			paste: "Wordle 1,234 2/6\n⬜️🟧🟧🟧🟧\n🟧🟧🟧🟧🟧\n" + token,
+		},
+		{
+			name:  "dark mode paste",
+			paste: "Wordle 1,234 2/6\n⬛🟩🟩🟩🟩\n🟩🟩🟩🟩🟩\n" + token,
+		},
+		{
+			name:  "spoiler paste",
+			paste: "Wordle 1,234 2/6\n⬜️🟩🟩🟩🟩 ||CELLO||\n🟩🟩🟩🟩🟩 ||HELLO||\n" + token,
+		},
+		{
+			name:  "ascii paste",
+			paste: "Wordle 1,234 2/6\n.####\n#####\n" + token,
+		},
+		{
+			name:    "spoiler paste with other guesses",
+			paste:   "Wordle 1,234 2/6\n⬜️🟩🟩🟩🟩 ||JELLO||\n🟩🟩🟩🟩🟩 ||HELLO||\n" + token,
+			wantErr: ErrShareInvalid,
+		},
+		{
+			name:    "ascii paste with a fake grid",
+			paste:   "Wordle 1,234 1/6\n#####\n" + token,
+			wantErr: ErrShareInvalid,
+		},
+		{
+			name:    "paste with a fake grid",
+			paste:   "Wordle 1,234 1/6\n🟩🟩🟩🟩🟩\n" + token,
+			wantErr: ErrShareInvalid,
+		},
+		{
+			name:    "paste without token",
+			paste:   "Wordle 1,234 2/6\n⬜️🟩🟩🟩🟩\n🟩🟩🟩🟩🟩",
+			wantErr: ErrTokenInvalid,
+		},
+		{
+			name:    "paste with a wrong token",
+			paste:   "Wordle 1,234 2/6\n⬜️🟩🟩🟩🟩\n🟩🟩🟩🟩🟩\n#00000000",
+			wantErr: ErrTokenInvalid,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := newFinishedGame().VerifyShare(test.paste, testKey)
+			if test.wantErr != nil {
+				assert.ErrorIs(t, err, test.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+
+	for _, format := range []ShareFormat{ShareNYT, ShareSpoiler, ShareASCII} {
+		t.Run("shared as "+string(format), func(t *testing.T) {
+			wordle := newFinishedGame()
+			WithShareToken(testKey)(wordle)
+			assert.NoError(t, wordle.VerifyShare(wordle.ShareAs(format), testKey))
+		})
+	}
+
+	t.Run("a game in progress can't be verified", func(t *testing.T) {
+		wordle := &Status{Wordle: "HELLO"}
+		assert.NoError(t, wordle.Try("CELLO"))
+		assert.ErrorIs(t, wordle.VerifyShare("", testKey), ErrNotFinished)
+	})
+
+	t.Run("a game saved before signing can't be verified", func(t *testing.T) {
+		wordle := newFinishedGame()
+		wordle.MAC = ""
+		assert.ErrorIs(t, wordle.VerifyShare(wordle.Share(), testKey), ErrUnverifiable)
+
+		wordle.Seal(testKey)
+		assert.ErrorIs(t, wordle.VerifyShare(wordle.Share(), testKey), ErrUnverifiable, "even once it's sealed")
+	})
+
+	t.Run("a tampered game can't be verified", func(t *testing.T) {
+		wordle := newFinishedGame()
+		wordle.Round = 1
+		assert.ErrorIs(t, wordle.VerifyShare("", testKey), ErrTampered)
+	})
+}
@@ -1,50 +1,300 @@
 package wordle
 
 import (
+	"errors"
 	"fmt"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"unicode"
 )
 
 const (
-	absentSquare  = "⬜️"
+	absentSquare  = whiteSquare + variationSelector
 	correctSquare = "🟩"
 	presentSquare = "🟨"
 	newLine       = "\n"
+
+	// Squares of the high contrast mode.
+	orangeSquare = "🟧"
+	blueSquare   = "🟦"
+
+	// Squares of the absent letters in the light and dark modes of the NYT game.
+	whiteSquare = "⬜"
+	blackSquare = "⬛"
+
+	maxAttempts = 6
+	wordLength  = 5
+)
+
+var (
+	ErrInvalidShare = errors.New("invalid shared result")
+
+	shareHeader = regexp.MustCompile(`^Wordle[\s\p{Zs}]+([\d,.]+)[\s\p{Zs}]+([1-6xX])/6[\s\p{Zs}]*(\*?)$`)
+	// slackEmojis are the names Slack gives to the squares in the text of messages.
+	slackEmojis = strings.NewReplacer(
+		":white_large_square:", whiteSquare,
+		":black_large_square:", blackSquare,
+		":large_green_square:", correctSquare,
+		":large_yellow_square:", presentSquare,
+		":large_orange_square:", orangeSquare,
+		":large_blue_square:", blueSquare,
+	)
+	squares = map[rune]int{
+		'⬜': Absent,
+		'⬛': Absent,
+		'🟩': Correct,
+		'🟨': Present,
+	}
+)
+
+// Shared is a result read from the text of Share.
+type Shared struct {
+	PuzzleNumber int
+	// Guesses is how many guesses it took to win, 0 when the game was lost.
+	Guesses  int
+	HardMode bool
+	// Rows are the results of every guess, Absent, Correct or Present for each letter.
+	Rows [][]int
+}
+
+func (s Shared) Won() bool {
+	return s.Guesses > 0
+}
+
+// Grid returns the rows as green, yellow and white squares.
+func (s Shared) Grid() []string {
+	grid := make([]string, 0, len(s.Rows))
+	for _, row := range s.Rows {
+		var squares string
+		for _, v := range row {
+			switch v {
+			case Correct:
+				squares += correctSquare
+			case Present:
+				squares += presentSquare
+			default:
+				squares += whiteSquare
+			}
+		}
+		grid = append(grid, squares)
+	}
+
+	return grid
+}
+
+// WithHighContrast shares the results with orange and blue squares
+// instead of green and yellow ones.
+func WithHighContrast() ConfigSetter {
+	return func(s *Status) {
+		s.highContrast = true
+	}
+}
+
+// WithDarkSquares shares the absent letters with black squares, as the
+// NYT game does in dark mode, instead of white ones.
+func WithDarkSquares() ConfigSetter {
+	return func(s *Status) {
+		s.darkSquares = true
+	}
+}
+
+// ShareFormat is how a result is shared.
+type ShareFormat string
+
+const (
+	// ShareNYT is the result as the NYT game shares it.
+	ShareNYT ShareFormat = "nyt"
+	// ShareSpoiler adds the guesses to the squares, hidden behind the
+	// ||spoiler|| marks of Markdown and chat apps.
+	ShareSpoiler ShareFormat = "spoiler"
+	// ShareASCII draws the squares with characters any terminal displays.
+	ShareASCII ShareFormat = "ascii"
 )
 
+// asciiSquares are the squares of ShareASCII by result.
+var asciiSquares = map[int]string{Absent: ".", Correct: "#", Present: "+"}
+
+// Share is the result in the NYT format.
 func (s *Status) Share() string {
+	return s.ShareAs(ShareNYT)
+}
+
+// ShareAs is the result in format f, the NYT one when f is unknown.
+func (s *Status) ShareAs(f ShareFormat) string {
 	n := strconv.Itoa(s.Round)
 	if string(s.Discovered[:]) != s.Wordle {
 		n = "X"
 	}
+	hard := ""
+	if s.HardMode {
+		hard = "*"
+	}
+
+	rows := s.squares()
+	switch f {
+	case ShareSpoiler:
+		for i, res := range s.Results {
+			rows[i] += " ||" + guess(res) + "||"
+		}
+	case ShareASCII:
+		for i, res := range s.Results {
+			rows[i] = ""
+			for _, v := range results(res) {
+				rows[i] += asciiSquares[v]
+			}
+		}
+	}
 
-	title := fmt.Sprintf("Wordle %d %s/6*", s.PuzzleNumber, n)
+	share := fmt.Sprintf("Wordle %s %s/6%s", thousands(s.PuzzleNumber), n, hard) + newLine + newLine + strings.Join(rows, newLine)
+	if s.shareKey != nil && !s.Unverified {
+		share += newLine + tokenPrefix + s.Token(s.shareKey)
+	}
 
-	return title + newLine + s.squaresString()
+	return share
 }
 
-func (s *Status) squaresString() string {
-	var finalResult []string
+// squares returns the emoji squares of every guess.
+func (s *Status) squares() []string {
+	correct, present, absent := correctSquare, presentSquare, absentSquare
+	if s.highContrast {
+		correct, present = orangeSquare, blueSquare
+	}
+	if s.darkSquares {
+		absent = blackSquare
+	}
 
+	rows := make([]string, 0, len(s.Results))
 	for _, res := range s.Results {
 		var row string
-		for _, stat := range res {
-			for _, v := range stat {
-				switch v {
-				case Correct:
-					row += correctSquare
-				case Present:
-					row += presentSquare
-				case Absent:
-					row += absentSquare
-				}
+		for _, v := range results(res) {
+			switch v {
+			case Correct:
+				row += correct
+			case Present:
+				row += present
+			case Absent:
+				row += absent
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+// results returns the result of every letter of a guess.
+func results(res []map[rune]int) []int {
+	var r []int
+	for _, stat := range res {
+		for _, v := range stat {
+			r = append(r, v)
+		}
+	}
+
+	return r
+}
+
+// guess returns the word of a guess.
+func guess(res []map[rune]int) string {
+	var word string
+	for _, stat := range res {
+		for l := range stat {
+			word += string(l)
+		}
+	}
+
+	return word
+}
+
+// thousands writes n with its thousands separated by commas.
+func thousands(n int) string {
+	s := strconv.Itoa(n)
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+
+	return s
+}
+
+// IsShareHeader reports whether line is the "Wordle 1,234 3/6" header a
+// shared result starts with.
+func IsShareHeader(line string) bool {
+	lines := normalizeShare(line)
+	return len(lines) == 1 && shareHeader.MatchString(lines[0])
+}
+
+// ParseShare reads the text produced by Share, in any of its color modes
+// or as shared by the NYT game with either dark or light squares. Blank
+// space around the squares, as chat apps may add, is ignored.
+func ParseShare(text string) (Shared, error) {
+	var s Shared
+	lines := normalizeShare(slackEmojis.Replace(text))
+	if len(lines) == 0 {
+		return s, fmt.Errorf("%w: empty result", ErrInvalidShare)
+	}
+
+	header := shareHeader.FindStringSubmatch(lines[0])
+	if header == nil {
+		return s, fmt.Errorf("%w: unknown header %q", ErrInvalidShare, lines[0])
+	}
+	number, err := strconv.Atoi(strings.NewReplacer(",", "", ".", "").Replace(header[1]))
+	if err != nil {
+		return s, fmt.Errorf("%w: invalid puzzle number %q", ErrInvalidShare, header[1])
+	}
+	s.PuzzleNumber = number
+	if !strings.EqualFold(header[2], "X") {
+		s.Guesses = int(header[2][0] - '0')
+	}
+	s.HardMode = header[3] == "*"
+
+	for _, l := range lines[1:] {
+		var row []int
+		for _, c := range l {
+			if unicode.IsSpace(c) {
+				continue
+			}
+			v, ok := squares[c]
+			if !ok {
+				break
 			}
+			row = append(row, v)
+		}
+		if len(row) == 0 {
+			// Anything after the grid, such as a verification token, is ignored.
+			break
+		}
+		s.Rows = append(s.Rows, row)
+	}
+
+	return s, s.Validate()
+}
+
+// Validate checks the rows match the number of guesses.
+func (s Shared) Validate() error {
+	if len(s.Rows) == 0 || len(s.Rows) > maxAttempts {
+		return fmt.Errorf("%w: %d rows", ErrInvalidShare, len(s.Rows))
+	}
+
+	for i, row := range s.Rows {
+		if len(row) != wordLength {
+			return fmt.Errorf("%w: row %d has %d squares", ErrInvalidShare, i+1, len(row))
+		}
+
+		solved := !slices.ContainsFunc(row, func(v int) bool { return v != Correct })
+		last := i == len(s.Rows)-1
+		if solved && !last {
+			return fmt.Errorf("%w: solved before the last row", ErrInvalidShare)
 		}
-		if row != "" {
-			finalResult = append(finalResult, row)
+		if last && solved != s.Won() {
+			return fmt.Errorf("%w: score doesn't match the grid", ErrInvalidShare)
 		}
 	}
 
-	return strings.Join(finalResult, newLine)
+	if s.Won() && s.Guesses != len(s.Rows) || !s.Won() && len(s.Rows) != maxAttempts {
+		return fmt.Errorf("%w: score doesn't match the grid", ErrInvalidShare)
+	}
+
+	return nil
 }
@@ -1,12 +1,24 @@
 package wordle
 
 import (
+	"bytes"
+	"flag"
+	"fmt"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+var update = flag.Bool("update", false, "Updates the golden files in testdata")
+
 func TestShareString(t *testing.T) {
 	t.Run("win in two tries", func(t *testing.T) {
 		wordle := &Status{Wordle: "HELLO"}
@@ -14,7 +26,7 @@ func TestShareString(t *testing.T) {
 		assert.NoError(t, wordle.Try("HELLO"))
 
 		got := wordle.Share()
-		want := "Wordle 0 2/6*" + newLine +
+		want := "Wordle 0 2/6" + newLine + newLine +
 			absentSquare + strings.Repeat(correctSquare, 4) +
 			newLine + strings.Repeat(correctSquare, 5)
 
@@ -29,7 +41,7 @@ func TestShareString(t *testing.T) {
 		}
 
 		got := wordle.Share()
-		want := "Wordle 0 6/6*" + newLine +
+		want := "Wordle 0 6/6" + newLine + newLine +
 			strings.Repeat(absentSquare, 5) + newLine +
 			strings.Repeat(absentSquare+strings.Repeat(correctSquare, 4)+newLine, 4) +
 			strings.Repeat(correctSquare, 5)
@@ -45,10 +57,301 @@ func TestShareString(t *testing.T) {
 		}
 
 		got := wordle.Share()
-		want := "Wordle 0 X/6*" + newLine +
+		want := "Wordle 0 X/6" + newLine + newLine +
 			strings.Repeat(strings.Repeat(absentSquare, 5)+newLine, 5) +
 			strings.Repeat(absentSquare, 5)
 
 		assert.Equal(t, want, got)
 	})
+
+	t.Run("high contrast", func(t *testing.T) {
+		wordle := &Status{Wordle: "HELLO"}
+		WithHighContrast()(wordle)
+		assert.NoError(t, wordle.Try("OLLIE"))
+		assert.NoError(t, wordle.Try("HELLO"))
+
+		got := wordle.Share()
+		want := "Wordle 0 2/6" + newLine + newLine +
+			strings.Repeat(blueSquare, 2) + orangeSquare + absentSquare + blueSquare +
+			newLine + strings.Repeat(orangeSquare, 5)
+
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("hard mode in a puzzle past 1,000", func(t *testing.T) {
+		wordle := &Status{Wordle: "HELLO", PuzzleNumber: 1234, HardMode: true}
+		assert.NoError(t, wordle.Try("HELLO"))
+
+		assert.Equal(t, "Wordle 1,234 1/6*"+newLine+newLine+strings.Repeat(correctSquare, 5), wordle.Share())
+	})
+}
+
+func TestShareAs(t *testing.T) {
+	wordle := &Status{Wordle: "HELLO", PuzzleNumber: 12}
+	assert.NoError(t, wordle.Try("OLLIE"))
+	assert.NoError(t, wordle.Try("HELLO"))
+
+	tests := map[ShareFormat]string{
+		ShareNYT:     "Wordle 12 2/6\n\n🟨🟨🟩⬜️🟨\n🟩🟩🟩🟩🟩",
+		ShareSpoiler: "Wordle 12 2/6\n\n🟨🟨🟩⬜️🟨 ||OLLIE||\n🟩🟩🟩🟩🟩 ||HELLO||",
+		ShareASCII:   "Wordle 12 2/6\n\n++#.+\n#####",
+		"unknown":    "Wordle 12 2/6\n\n🟨🟨🟩⬜️🟨\n🟩🟩🟩🟩🟩",
+	}
+	for format, want := range tests {
+		t.Run(string(format), func(t *testing.T) {
+			assert.Equal(t, want, wordle.ShareAs(format))
+		})
+	}
+
+	t.Run("every format is read back", func(t *testing.T) {
+		for _, format := range []ShareFormat{ShareNYT, ShareSpoiler} {
+			got, err := ParseShare(wordle.ShareAs(format))
+			assert.NoError(t, err)
+			assert.Equal(t, 2, got.Guesses)
+		}
+	})
+}
+
+// TestShareGolden compares every format and color mode with the results
+// in testdata, run with -update after changing them on purpose.
+func TestShareGolden(t *testing.T) {
+	game := func(word string, hard bool, tries ...string) *Status {
+		s := &Status{Wordle: word, PuzzleNumber: 1234, HardMode: hard}
+		for _, try := range tries {
+			require.NoError(t, s.Try(try))
+		}
+		return s
+	}
+	won := func() *Status { return game("HELLO", false, "OLLIE", "CELLO", "HELLO") }
+
+	tests := []struct {
+		name   string
+		game   *Status
+		format ShareFormat
+		conf   []ConfigSetter
+	}{
+		{name: "nyt", game: won(), format: ShareNYT},
+		{name: "nyt_dark", game: won(), format: ShareNYT, conf: []ConfigSetter{WithDarkSquares()}},
+		{name: "nyt_hard_mode", game: game("HELLO", true, "CELLO", "HELLO"), format: ShareNYT},
+		{name: "nyt_lost", game: game("LIGHT", false, "SCARF", "MIGHT", "FIGHT", "TIGHT", "RIGHT", "NIGHT"), format: ShareNYT},
+		{name: "nyt_high_contrast", game: won(), format: ShareNYT, conf: []ConfigSetter{WithHighContrast()}},
+		{name: "nyt_high_contrast_dark", game: won(), format: ShareNYT, conf: []ConfigSetter{WithHighContrast(), WithDarkSquares()}},
+		{name: "spoiler", game: won(), format: ShareSpoiler},
+		{name: "spoiler_dark", game: won(), format: ShareSpoiler, conf: []ConfigSetter{WithDarkSquares()}},
+		{name: "ascii", game: won(), format: ShareASCII},
+	}
+	t.Run("ascii has no dark squares", func(t *testing.T) {
+		game := won()
+		WithDarkSquares()(game)
+		assert.Equal(t, won().ShareAs(ShareASCII), game.ShareAs(ShareASCII))
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, c := range tt.conf {
+				c(tt.game)
+			}
+			got := tt.game.ShareAs(tt.format)
+
+			path := filepath.Join("testdata", tt.name+".golden")
+			if *update {
+				require.NoError(t, os.WriteFile(path, []byte(got), 0600))
+			}
+			want, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, string(want), got)
+
+			shared, err := ParseShare(got)
+			if tt.format != ShareASCII {
+				assert.NoError(t, err, "every emoji format is read back")
+				assert.Equal(t, tt.game.HardMode, shared.HardMode)
+			}
+		})
+	}
+}
+
+func TestShareImage(t *testing.T) {
+	wordle := &Status{Wordle: "HELLO"}
+	assert.NoError(t, wordle.Try("OLLIE"))
+	assert.NoError(t, wordle.Try("HELLO"))
+
+	data, err := wordle.ShareImage()
+	assert.NoError(t, err)
+	img, err := png.Decode(bytes.NewReader(data))
+	assert.NoError(t, err)
+
+	tile := func(row, col int) color.Color {
+		return img.At(boardPad+col*(tileSize+tileGap)+tileSize/2, boardPad+row*(tileSize+tileGap)+tileSize/2)
+	}
+	assert.Equal(t, 2*boardPad+5*tileSize+4*tileGap, img.Bounds().Dx())
+	assert.Equal(t, 2*boardPad+6*tileSize+5*tileGap, img.Bounds().Dy())
+	assert.Equal(t, presentColor, color.RGBAModel.Convert(tile(0, 0)))
+	assert.Equal(t, correctColor, color.RGBAModel.Convert(tile(0, 2)))
+	assert.Equal(t, absentColor, color.RGBAModel.Convert(tile(0, 3)))
+	assert.Equal(t, backgroundColor, color.RGBAModel.Convert(tile(2, 0)), "rows not played are empty")
+
+	t.Run("dark squares", func(t *testing.T) {
+		WithDarkSquares()(wordle)
+		data, err := wordle.ShareImage()
+		assert.NoError(t, err)
+		img, err = png.Decode(bytes.NewReader(data))
+		assert.NoError(t, err)
+
+		assert.Equal(t, darkAbsentColor, color.RGBAModel.Convert(tile(0, 3)))
+		assert.Equal(t, darkBackgroundColor, color.RGBAModel.Convert(tile(2, 0)))
+		assert.Equal(t, darkBackgroundColor, color.RGBAModel.Convert(img.At(0, 0)))
+	})
+}
+
+func TestParseShare(t *testing.T) {
+	won := []int{Correct, Correct, Correct, Correct, Correct}
+	tests := []struct {
+		name    string
+		share   string
+		want    Shared
+		wantErr string
+	}{
+		{
+			name:  "win in hard mode",
+			share: "Wordle 1,234 2/6*\n⬜️🟨⬛🟩🟩\n🟩🟩🟩🟩🟩",
+			want:  Shared{PuzzleNumber: 1234, Guesses: 2, HardMode: true, Rows: [][]int{{Absent, Present, Absent, Correct, Correct}, won}},
+		},
+		{
+			name:  "loss",
+			share: "Wordle 12 X/6\n" + strings.Repeat("⬜⬜⬜⬜⬜\n", 6),
+			want:  Shared{PuzzleNumber: 12, Rows: [][]int{make([]int, 5), make([]int, 5), make([]int, 5), make([]int, 5), make([]int, 5), make([]int, 5)}},
+		},
+		{
+			name:  "high contrast with a verification token",
+			share: "Wordle 12 2/6\n🟦⬜🟧⬜⬜\n🟧🟧🟧🟧🟧\n#abcdef12",
+			want:  Shared{PuzzleNumber: 12, Guesses: 2, Rows: [][]int{{Present, Absent, Correct, Absent, Absent}, won}},
+		},
+		{
+			name:  "dark mode as shared by the NYT game",
+			share: "Wordle 1,234 3/6*\n\n⬛⬛🟨⬛⬛\n⬛🟩⬛🟩⬛\n🟩🟩🟩🟩🟩",
+			want:  Shared{PuzzleNumber: 1234, Guesses: 3, HardMode: true, Rows: [][]int{{Absent, Absent, Present, Absent, Absent}, {Absent, Correct, Absent, Correct, Absent}, won}},
+		},
+		{
+			name:  "stray whitespace",
+			share: "  Wordle\u00a01.234  1/6 *\r\n\t🟩 🟩\u00a0🟩🟩 🟩  \r\n",
+			want:  Shared{PuzzleNumber: 1234, Guesses: 1, HardMode: true, Rows: [][]int{won}},
+		},
+		{
+			name:  "lowercase loss",
+			share: "Wordle 12 x/6\n" + strings.Repeat("⬛⬛⬛⬛🟨\n", 6),
+			want:  Shared{PuzzleNumber: 12, Rows: slices.Repeat([][]int{{Absent, Absent, Absent, Absent, Present}}, 6)},
+		},
+		{
+			name:  "slack emoji names",
+			share: "Wordle 12 2/6\n:white_large_square::large_yellow_square::black_large_square::large_orange_square::large_blue_square:\n:large_green_square::large_green_square::large_green_square::large_green_square::large_green_square:",
+			want:  Shared{PuzzleNumber: 12, Guesses: 2, Rows: [][]int{{Absent, Present, Absent, Correct, Present}, won}},
+		},
+		{name: "empty", share: " \n", wantErr: "invalid shared result: empty result"},
+		{name: "bad header", share: "Wordle 12 7/6\n🟩🟩🟩🟩🟩", wantErr: `invalid shared result: unknown header "Wordle 12 7/6"`},
+		{name: "no grid", share: "Wordle 12 1/6", wantErr: "invalid shared result: 0 rows"},
+		{name: "short row", share: "Wordle 12 1/6\n🟩🟩🟩🟩", wantErr: "invalid shared result: row 1 has 4 squares"},
+		{name: "score doesn't match the rows", share: "Wordle 12 2/6\n🟩🟩🟩🟩🟩", wantErr: "invalid shared result: score doesn't match the grid"},
+		{name: "solved before the last row", share: "Wordle 12 2/6\n🟩🟩🟩🟩🟩\n🟩🟩🟩🟩🟩", wantErr: "invalid shared result: solved before the last row"},
+		{name: "lost with less than 6 rows", share: "Wordle 12 X/6\n⬜⬜⬜⬜⬜", wantErr: "invalid shared result: score doesn't match the grid"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParseShare(test.share)
+			if test.wantErr != "" {
+				assert.ErrorIs(t, err, ErrInvalidShare)
+				assert.EqualError(t, err, test.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+
+	t.Run("reads what Share writes", func(t *testing.T) {
+		game := &Status{Wordle: "HELLO", PuzzleNumber: 1500}
+		assert.NoError(t, game.Try("CELLO"))
+		assert.NoError(t, game.Try("HELLO"))
+
+		got, err := ParseShare(game.Share())
+		assert.NoError(t, err)
+		assert.Equal(t, 1500, got.PuzzleNumber)
+		assert.Equal(t, 2, got.Guesses)
+		assert.True(t, got.Won())
+	})
+}
+
+func FuzzParseShare(f *testing.F) {
+	f.Add("Wordle 1,234 3/6*\n⬛🟨⬛⬛⬛\n⬛🟩🟩⬛🟩\n🟩🟩🟩🟩🟩")
+	f.Add("Wordle 12 X/6\n" + strings.Repeat("⬜️⬜️🟨⬜️⬜️\n", 6))
+	f.Add("Wordle 12 2/6\n🟦⬜🟧⬜⬜\n🟧🟧🟧🟧🟧\n#abcdef12")
+	f.Add(" Wordle 1.234 1/6 *\r\n🟩 🟩 🟩 🟩 🟩")
+	f.Add("Wordle 12 1/6\n:large_green_square::large_green_square::large_green_square::large_green_square::large_green_square:")
+
+	f.Fuzz(func(t *testing.T, text string) {
+		got, err := ParseShare(text)
+		if err != nil {
+			assert.ErrorIs(t, err, ErrInvalidShare)
+			return
+		}
+
+		assert.GreaterOrEqual(t, got.PuzzleNumber, 0)
+		assert.NotEmpty(t, got.Rows)
+		assert.LessOrEqual(t, len(got.Rows), maxAttempts)
+		if got.Won() {
+			assert.Len(t, got.Rows, got.Guesses)
+		} else {
+			assert.Len(t, got.Rows, maxAttempts)
+		}
+		for i, row := range got.Rows {
+			solved := !slices.ContainsFunc(row, func(v int) bool { return v != Correct })
+			assert.Len(t, row, wordLength)
+			assert.Equal(t, got.Won() && i == len(got.Rows)-1, solved)
+		}
+
+		// What's read is written back the same way.
+		again, err := ParseShare(shareText(got))
+		assert.NoError(t, err)
+		assert.Equal(t, got, again)
+	})
+}
+
+// shareText writes a shared result like Share does.
+func shareText(s Shared) string {
+	n, hard := "X", ""
+	if s.Won() {
+		n = strconv.Itoa(s.Guesses)
+	}
+	if s.HardMode {
+		hard = "*"
+	}
+
+	text := fmt.Sprintf("Wordle %d %s/6%s", s.PuzzleNumber, n, hard)
+	for _, row := range s.Rows {
+		text += newLine
+		for _, v := range row {
+			text += map[int]string{Absent: absentSquare, Correct: correctSquare, Present: presentSquare}[v]
+		}
+	}
+
+	return text
+}
+
+func TestSharedGrid(t *testing.T) {
+	s := Shared{Guesses: 2, Rows: [][]int{{Absent, Present, Absent, Correct, Correct}, {Correct, Correct, Correct, Correct, Correct}}}
+
+	assert.NoError(t, s.Validate())
+	assert.Equal(t, []string{"⬜🟨⬜🟩🟩", "🟩🟩🟩🟩🟩"}, s.Grid())
+}
+
+func TestIsShareHeader(t *testing.T) {
+	for line, want := range map[string]bool{
+		"Wordle 1,234 3/6*":         true,
+		"  Wordle\u00a01234\tX/6  ": true,
+		"Wordle 1234 7/6":           false,
+		"Wordle is fun":             false,
+		"🟩🟩🟩🟩🟩":                     false,
+	} {
+		assert.Equal(t, want, IsShareHeader(line), line)
+	}
 }
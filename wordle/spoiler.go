@@ -0,0 +1,86 @@
+package wordle
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// plainStatus has the same fields as Status but none of its methods,
+// so it can be used inside the custom JSON (un)marshalers without recursion.
+type plainStatus Status
+
+// savedStatus is the on-disk representation of a Status. While the game is
+// in progress only a hash of the answer, keyed with the key the game is
+// sealed with, is stored so a look at the status file won't spoil the
+// game. The answer is stored in plain text once the game is finished.
+type savedStatus struct {
+	plainStatus
+	Wordle     string `json:"wordle,omitempty"`
+	WordleHash string `json:"wordle_hash,omitempty"`
+}
+
+func (s Status) MarshalJSON() ([]byte, error) {
+	saved := savedStatus{plainStatus: plainStatus(s)}
+	switch {
+	case s.Wordle == "":
+		saved.WordleHash = s.wordleHash
+	case s.Finish():
+		saved.Wordle = s.Wordle
+	case s.key != nil:
+		saved.WordleHash = hashWordle(s.key, s.PuzzleNumber, s.Wordle)
+	}
+
+	return json.Marshal(saved)
+}
+
+// UnmarshalJSON reads both the hashed format and older status files
+// where the answer was always stored in plain text.
+func (s *Status) UnmarshalJSON(data []byte) error {
+	var saved savedStatus
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return err
+	}
+
+	*s = Status(saved.plainStatus)
+	s.Wordle = saved.Wordle
+	s.wordleHash = saved.WordleHash
+
+	return nil
+}
+
+// matches checks if the given word is the answer of the status. When the
+// status has been loaded from a game in progress the answer is unknown and
+// only its hash can be compared, with the key the status was verified with.
+func (s *Status) matches(word string) bool {
+	if s.Wordle != "" {
+		return s.Wordle == word
+	}
+
+	return s.wordleHash != "" && s.key != nil && hmac.Equal([]byte(s.wordleHash), []byte(hashWordle(s.key, s.PuzzleNumber, word)))
+}
+
+// rekey hashes the answer of a game in progress with key instead of the
+// key it was verified with, i.e. when it's moved to another profile. Only
+// the hash of the answer is known, so it's looked up among the words.
+func (s *Status) rekey(key []byte) {
+	if s.Wordle != "" || s.wordleHash == "" || s.key == nil || hmac.Equal(s.key, key) {
+		return
+	}
+
+	for _, w := range s.words() {
+		if s.matches(w) {
+			s.wordleHash = hashWordle(key, s.PuzzleNumber, w)
+			return
+		}
+	}
+}
+
+func hashWordle(key []byte, puzzleNumber int, word string) string {
+	h := hmac.New(sha256.New, key)
+	fmt.Fprintf(h, "%d:%s", puzzleNumber, word)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
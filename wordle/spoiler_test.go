@@ -0,0 +1,110 @@
+package wordle
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpoilerSafeJSON(t *testing.T) {
+	key := []byte("test key")
+
+	t.Run("game in progress does not contain the answer", func(t *testing.T) {
+		wordle := &Status{Wordle: "HELLO", PuzzleNumber: 123}
+		assert.NoError(t, wordle.Try("CHAIR"))
+		wordle.Seal(key)
+
+		got, err := json.Marshal(wordle)
+		assert.NoError(t, err)
+		assert.NotContains(t, string(got), "HELLO")
+		assert.Contains(t, string(got), `"wordle_hash":"`+hashWordle(key, 123, "HELLO")+`"`)
+	})
+
+	t.Run("the hash can't be looked up without the key", func(t *testing.T) {
+		assert.NotEqual(t, hashWordle(key, 123, "HELLO"), hashWordle([]byte("other key"), 123, "HELLO"))
+
+		wordle := &Status{Wordle: "HELLO", PuzzleNumber: 123}
+		assert.NoError(t, wordle.Try("CHAIR"))
+		got, err := json.Marshal(wordle)
+		assert.NoError(t, err)
+		assert.NotContains(t, string(got), "wordle", "a game never sealed has no hash to look up")
+	})
+
+	t.Run("finished game reveals the answer", func(t *testing.T) {
+		wordle := &Status{Wordle: "HELLO", PuzzleNumber: 123}
+		assert.NoError(t, wordle.Try("HELLO"))
+
+		got, err := json.Marshal(wordle)
+		assert.NoError(t, err)
+		assert.Contains(t, string(got), `"wordle":"HELLO"`)
+		assert.NotContains(t, string(got), "wordle_hash")
+	})
+
+	t.Run("saved game in progress is restored when the answer matches the hash", func(t *testing.T) {
+		wordle := &Status{Wordle: "HELLO", PuzzleNumber: 123}
+		assert.NoError(t, wordle.Try("CHAIR"))
+		wordle.Seal(key)
+		data, err := json.Marshal(wordle)
+		assert.NoError(t, err)
+
+		saved := &Status{}
+		assert.NoError(t, json.Unmarshal(data, saved))
+		assert.Empty(t, saved.Wordle)
+		assert.NoError(t, saved.Verify(key))
+
+		today := &Status{Wordle: "HELLO", PuzzleNumber: 123}
+		WithSavedWordle(saved)(today)
+		assert.Equal(t, "HELLO", today.Wordle)
+		assert.Equal(t, 1, today.Round)
+		assert.NoError(t, today.Try("HELLO"))
+		assert.True(t, today.Finish())
+	})
+
+	t.Run("saved game in progress is discarded when the answer does not match the hash", func(t *testing.T) {
+		wordle := &Status{Wordle: "WORLD", PuzzleNumber: 123}
+		assert.NoError(t, wordle.Try("CHAIR"))
+		wordle.Seal(key)
+		data, err := json.Marshal(wordle)
+		assert.NoError(t, err)
+
+		saved := &Status{}
+		assert.NoError(t, json.Unmarshal(data, saved))
+		assert.NoError(t, saved.Verify(key))
+
+		today := &Status{Wordle: "HELLO", PuzzleNumber: 123}
+		WithSavedWordle(saved)(today)
+		assert.Equal(t, 0, today.Round)
+	})
+
+	t.Run("a game sealed again with another key still matches", func(t *testing.T) {
+		other := []byte("other key")
+		wordle := &Status{Wordle: "HELLO", PuzzleNumber: 123}
+		assert.NoError(t, wordle.Try("CHAIR"))
+		wordle.Seal(key)
+		data, err := json.Marshal(wordle)
+		assert.NoError(t, err)
+
+		moved := &Status{}
+		assert.NoError(t, json.Unmarshal(data, moved))
+		assert.NoError(t, moved.Verify(key))
+		moved.Seal(other)
+		data, err = json.Marshal(moved)
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), hashWordle(other, 123, "HELLO"))
+
+		saved := &Status{}
+		assert.NoError(t, json.Unmarshal(data, saved))
+		assert.NoError(t, saved.Verify(other))
+		today := &Status{Wordle: "HELLO", PuzzleNumber: 123}
+		WithSavedWordle(saved)(today)
+		assert.Equal(t, 1, today.Round)
+	})
+
+	t.Run("older status files with the answer in plain text are read", func(t *testing.T) {
+		saved := &Status{}
+		assert.NoError(t, json.Unmarshal([]byte(`{"round":1,"puzzle_number":123,"wordle":"HELLO"}`), saved))
+		assert.Equal(t, "HELLO", saved.Wordle)
+		assert.True(t, saved.matches("HELLO"))
+	})
+}
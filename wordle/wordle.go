@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"math/rand/v2"
 	"net/http"
 	"slices"
 	"strings"
@@ -19,6 +20,20 @@ const (
 	wordleBaseURL = "https://www.nytimes.com/svc/wordle/v2/%s.json"
 )
 
+// firstPuzzle is the day Wordle 0 was published.
+var firstPuzzle = time.Date(2021, time.June, 19, 0, 0, 0, 0, time.UTC)
+
+// PuzzleOn returns the number of the puzzle published on the day of t.
+func PuzzleOn(t time.Time) int {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return int(day.Sub(firstPuzzle).Hours() / 24)
+}
+
+// PuzzleDate returns the day puzzle n was published.
+func PuzzleDate(n int) time.Time {
+	return firstPuzzle.AddDate(0, 0, n)
+}
+
 // Allowed list: https://gist.github.com/cfreshman/d5fb56316158a1575898bba1eed3b5da
 // Answers list: https://gist.github.com/cfreshman/a7b776506c73284511034e63af1017ee
 var (
@@ -33,22 +48,35 @@ var (
 type Status struct {
 	Round        int              `json:"round"`
 	PuzzleNumber int              `json:"puzzle_number"`
-	Wordle       string           `json:"wordle"`
+	Wordle       string           `json:"-"`
 	HardMode     bool             `json:"hard_mode"`
 	Results      [][]map[rune]int `json:"results"`
 	Discovered   [5]rune          `json:"discovered"`
 	Hints        []rune           `json:"hints"`
 	Used         []rune           `json:"used"`
+	MAC          string           `json:"mac,omitempty"`
+	// Unverified marks games saved before they were signed, whose results
+	// can't be vouched for.
+	Unverified bool `json:"unverified,omitempty"`
 
 	allowedWords []string
+	wordleHash   string
+	shareKey     []byte
+	// key is the key the status was sealed or verified with, which the
+	// hash of the answer is keyed with.
+	key          []byte
+	highContrast bool
+	darkSquares  bool
 }
 
 type ConfigSetter func(*Status)
 
 func WithSavedWordle(saved *Status) ConfigSetter {
 	return func(status *Status) {
-		if saved != nil && saved.Wordle == status.Wordle {
+		if saved != nil && saved.matches(status.Wordle) {
+			w := status.Wordle
 			*status = *saved
+			status.Wordle = w
 		}
 	}
 }
@@ -60,13 +88,24 @@ func WithCustomWord(w string) ConfigSetter {
 }
 
 func NewGame(hard bool, conf ...ConfigSetter) *Status {
+	s, err := TodaysGame(hard, conf...)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return s
+}
+
+// TodaysGame is like NewGame but returns an error
+// when today's wordle can't be fetched.
+func TodaysGame(hard bool, conf ...ConfigSetter) (*Status, error) {
 	return newCustomClientGame(hard, http.DefaultClient, conf...)
 }
 
-func newCustomClientGame(hard bool, httpClient *http.Client, conf ...ConfigSetter) *Status {
+func newCustomClientGame(hard bool, httpClient *http.Client, conf ...ConfigSetter) (*Status, error) {
 	w, pn, err := fetchTodaysWordle(httpClient)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 	s := &Status{HardMode: hard, Wordle: w, PuzzleNumber: pn}
 
@@ -74,7 +113,13 @@ func newCustomClientGame(hard bool, httpClient *http.Client, conf ...ConfigSette
 		confSetter(s)
 	}
 
-	return s
+	return s, nil
+}
+
+// RandomAnswer returns a random word from the answers list.
+func RandomAnswer() string {
+	answers := strings.Fields(strings.ToUpper(answersList))
+	return answers[rand.IntN(len(answers))] //nolint: gosec
 }
 
 func (s *Status) Try(word string) error {
@@ -94,17 +139,23 @@ func (s *Status) Finish() bool {
 }
 
 func (s *Status) isAllowed(word string) error {
+	if !slices.Contains(s.words(), word) {
+		return fmt.Errorf("Not in word list: %s", word) //nolint: stylecheck
+	}
+
+	return nil
+}
+
+// words returns every word that can be guessed.
+func (s *Status) words() []string {
 	if s.allowedWords == nil {
 		s.allowedWords = slices.Concat(
 			strings.Split(strings.ToUpper(allowedList), "\n"),
 			strings.Split(strings.ToUpper(answersList), "\n"),
 		)
 	}
-	if !slices.Contains(s.allowedWords, word) {
-		return fmt.Errorf("Not in word list: %s", word) //nolint: stylecheck
-	}
 
-	return nil
+	return s.allowedWords
 }
 
 func (s *Status) hardModeCheck(word string) error {
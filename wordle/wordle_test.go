@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -182,17 +183,28 @@ func TestNewGame(t *testing.T) {
 				StatusCode: http.StatusOK,
 			}}}
 
-			var got *Status
+			var (
+				got *Status
+				err error
+			)
 			if tt.settings == nil {
-				got = newCustomClientGame(tt.hardMode, client)
+				got, err = newCustomClientGame(tt.hardMode, client)
 			} else {
-				got = newCustomClientGame(tt.hardMode, client, tt.settings)
+				got, err = newCustomClientGame(tt.hardMode, client, tt.settings)
 			}
+			assert.NoError(t, err)
 			assert.Equal(t, tt.wantWordle, got)
 		})
 	}
 }
 
+func TestNewGameError(t *testing.T) {
+	client := &http.Client{Transport: &mockNYTAPI{resp: &http.Response{StatusCode: http.StatusNotFound}}}
+	got, err := newCustomClientGame(false, client)
+	assert.Error(t, err)
+	assert.Nil(t, got)
+}
+
 type mockNYTAPI struct {
 	resp *http.Response
 }
@@ -244,3 +256,16 @@ func TestFetchDailyWordle(t *testing.T) {
 		})
 	}
 }
+
+func TestRandomAnswer(t *testing.T) {
+	answer := RandomAnswer()
+	assert.Len(t, answer, 5)
+	assert.Contains(t, strings.ToUpper(answersList), answer)
+	assert.NoError(t, (&Status{Wordle: answer}).Try(answer))
+}
+
+func TestPuzzleOn(t *testing.T) {
+	assert.Equal(t, 0, PuzzleOn(time.Date(2021, time.June, 19, 23, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 1234, PuzzleOn(time.Date(2024, time.November, 4, 8, 0, 0, 0, time.FixedZone("CET", 3600))))
+	assert.Equal(t, time.Date(2024, time.November, 4, 0, 0, 0, 0, time.UTC), PuzzleDate(1234))
+}